@@ -0,0 +1,92 @@
+package gendoc
+
+import (
+	"regexp"
+	"strings"
+)
+
+// admonitionRegex matches the start of an admonition, e.g. "NOTE: some text".
+var admonitionRegex = regexp.MustCompile(`^\s*(NOTE|WARNING|TODO|IMPORTANT):\s*(.*)$`)
+
+// Admonition is a callout in a description, i.e. a paragraph starting with "NOTE:", "WARNING:", "TODO:", or
+// "IMPORTANT:". Type is the lower cased prefix (e.g. "note") and Text the rest of the paragraph.
+type Admonition struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// parseAdmonitions returns the admonitions in desc. An admonition runs until the end of its paragraph, or until the
+// next admonition. Fenced code blocks are skipped.
+func parseAdmonitions(desc string) []Admonition {
+	var admonitions []Admonition
+	var current *Admonition
+	inFence := false
+
+	for _, line := range strings.Split(desc, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+			current = nil
+			continue
+		}
+
+		switch match := admonitionRegex.FindStringSubmatch(line); {
+		case inFence || trimmed == "":
+			current = nil
+		case match != nil:
+			admonitions = append(admonitions, Admonition{Type: strings.ToLower(match[1]), Text: strings.TrimSpace(match[2])})
+			current = &admonitions[len(admonitions)-1]
+		case current != nil:
+			current.Text = strings.TrimSpace(current.Text + " " + trimmed)
+		}
+	}
+
+	return admonitions
+}
+
+// resolveAdmonitions parses the admonitions of all elements in the template (see TemplateOptions.Admonitions).
+func resolveAdmonitions(t *Template) {
+	for _, f := range t.Files {
+		f.admonitions = parseAdmonitions(f.Description)
+		for _, m := range f.Messages {
+			m.admonitions = parseAdmonitions(m.Description)
+			for _, field := range m.Fields {
+				field.admonitions = parseAdmonitions(field.Description)
+			}
+		}
+		for _, e := range f.Enums {
+			e.admonitions = parseAdmonitions(e.Description)
+			for _, v := range e.Values {
+				v.admonitions = parseAdmonitions(v.Description)
+			}
+		}
+		for _, s := range f.Services {
+			s.admonitions = parseAdmonitions(s.Description)
+			for _, m := range s.Methods {
+				m.admonitions = parseAdmonitions(m.Description)
+			}
+		}
+	}
+}
+
+// Admonitions returns the callouts (e.g. "NOTE: ...") in the description of this file. They're only parsed when
+// TemplateOptions.Admonitions is set, the description itself is left as is.
+func (f File) Admonitions() []Admonition { return f.admonitions }
+
+// Admonitions returns the callouts in the description of this message, see File.Admonitions.
+func (m Message) Admonitions() []Admonition { return m.admonitions }
+
+// Admonitions returns the callouts in the description of this field, see File.Admonitions.
+func (f MessageField) Admonitions() []Admonition { return f.admonitions }
+
+// Admonitions returns the callouts in the description of this enum, see File.Admonitions.
+func (e Enum) Admonitions() []Admonition { return e.admonitions }
+
+// Admonitions returns the callouts in the description of this enum value, see File.Admonitions.
+func (v EnumValue) Admonitions() []Admonition { return v.admonitions }
+
+// Admonitions returns the callouts in the description of this service, see File.Admonitions.
+func (s Service) Admonitions() []Admonition { return s.admonitions }
+
+// Admonitions returns the callouts in the description of this method, see File.Admonitions.
+func (m ServiceMethod) Admonitions() []Admonition { return m.admonitions }
@@ -0,0 +1,43 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+	. "github.com/pseudomuto/protoc-gen-doc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdmonitions(t *testing.T) {
+	comment := " A thing.\n\n NOTE: Things are\n cached for a minute.\n WARNING: Don't delete things.\n\n ```\n TODO: not an admonition\n ```\n TODO:add more things\n"
+	fd := &descriptor.FileDescriptorProto{
+		Name:    proto.String("admonitions.proto"),
+		Package: proto.String("test"),
+		MessageType: []*descriptor.DescriptorProto{{
+			Name:  proto.String("Thing"),
+			Field: []*descriptor.FieldDescriptorProto{newTestField("id", 1, descriptor.FieldDescriptorProto_TYPE_STRING, "")},
+		}},
+		SourceCodeInfo: &descriptor.SourceCodeInfo{
+			Location: []*descriptor.SourceCodeInfo_Location{
+				{Path: []int32{4, 0}, LeadingComments: proto.String(comment)},
+				{Path: []int32{4, 0, 2, 0}, LeadingComments: proto.String(" The id. Note: lower case doesn't count.\n")},
+			},
+		},
+	}
+
+	thing := findMessage("Thing", newTestTemplate(fd).Files[0])
+	require.Nil(t, thing.Admonitions())
+
+	tmpl := newTestTemplateWithOptions(TemplateOptions{Admonitions: true}, fd)
+
+	thing = findMessage("Thing", tmpl.Files[0])
+	require.Equal(t, []Admonition{
+		{Type: "note", Text: "Things are cached for a minute."},
+		{Type: "warning", Text: "Don't delete things."},
+		{Type: "todo", Text: "add more things"},
+	}, thing.Admonitions())
+	require.Contains(t, thing.Description, "NOTE: Things are\ncached for a minute.")
+	require.Empty(t, findField("id", thing).Admonitions())
+	require.Empty(t, tmpl.Files[0].Admonitions())
+}
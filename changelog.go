@@ -0,0 +1,67 @@
+package gendoc
+
+// ChangelogEntry is an element that was added in some version, see Template.Changelog.
+type ChangelogEntry struct {
+	Kind     string `json:"kind"`
+	FullName string `json:"fullName"`
+	File     string `json:"file"`
+}
+
+// Changelog returns the elements added in each version, keyed by version. The version of an element is set with the
+// `@since <version>` directive. Methods without it fall back to their `@version`. Elements without version info are
+// skipped, as are excluded files and elements. Entries are listed in the order of the Template.
+func (t *Template) Changelog() map[string][]ChangelogEntry {
+	changelog := make(map[string][]ChangelogEntry)
+	add := func(version, kind, fullName string, f *File) {
+		if version != "" {
+			changelog[version] = append(changelog[version], ChangelogEntry{Kind: kind, FullName: fullName, File: f.Name})
+		}
+	}
+
+	for _, f := range t.Files {
+		if f.Exclude {
+			continue
+		}
+
+		for _, m := range f.Messages {
+			if m.Exclude {
+				continue
+			}
+			add(m.Since, ElementMessage, m.FullName, f)
+			for _, field := range m.Fields {
+				add(field.Since, ElementField, m.FullName+"."+field.Name, f)
+			}
+		}
+		for _, e := range f.Enums {
+			if e.Exclude {
+				continue
+			}
+			add(e.Since, ElementEnum, e.FullName, f)
+			for _, v := range e.Values {
+				add(v.Since, ElementEnumValue, e.FullName+"."+v.Name, f)
+			}
+		}
+		for _, s := range f.Services {
+			if s.Exclude {
+				continue
+			}
+			add(s.Since, ElementService, s.FullName, f)
+			for _, m := range s.Methods {
+				if m.Exclude {
+					continue
+				}
+				add(defaultString(m.Since, m.Version), ElementMethod, s.FullName+"."+m.Name, f)
+			}
+		}
+	}
+
+	return changelog
+}
+
+// defaultString returns value, or def when value is empty.
+func defaultString(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
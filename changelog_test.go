@@ -0,0 +1,82 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/pseudomuto/protoc-gen-doc"
+)
+
+func TestChangelog(t *testing.T) {
+	comment := func(text string, path ...int32) *descriptor.SourceCodeInfo_Location {
+		return &descriptor.SourceCodeInfo_Location{Path: path, LeadingComments: proto.String(text)}
+	}
+
+	tmpl := newTestTemplate(&descriptor.FileDescriptorProto{
+		Name:    proto.String("changelog.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptor.DescriptorProto{
+			{
+				Name: proto.String("Thing"),
+				Field: []*descriptor.FieldDescriptorProto{
+					newTestField("id", 1, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+					newTestField("color", 2, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+				},
+			},
+			{Name: proto.String("Hidden")},
+		},
+		EnumType: []*descriptor.EnumDescriptorProto{{
+			Name: proto.String("Kind"),
+			Value: []*descriptor.EnumValueDescriptorProto{
+				{Name: proto.String("KIND_UNSPECIFIED"), Number: proto.Int32(0)},
+				{Name: proto.String("KIND_LARGE"), Number: proto.Int32(1)},
+			},
+		}},
+		Service: []*descriptor.ServiceDescriptorProto{{
+			Name: proto.String("ThingService"),
+			Method: []*descriptor.MethodDescriptorProto{
+				{Name: proto.String("Get"), InputType: proto.String(".test.Thing"), OutputType: proto.String(".test.Thing")},
+				{Name: proto.String("Paint"), InputType: proto.String(".test.Thing"), OutputType: proto.String(".test.Thing")},
+			},
+		}},
+		SourceCodeInfo: &descriptor.SourceCodeInfo{
+			Location: []*descriptor.SourceCodeInfo_Location{
+				comment("A thing. @since v1", 4, 0),
+				comment("@since v2", 4, 0, 2, 1),
+				comment("@since v2 @exclude", 4, 1),
+				comment("@since v2", 5, 0, 2, 1),
+				comment("@since v1", 6, 0),
+				comment("@version v2", 6, 0, 2, 1),
+			},
+		},
+	})
+
+	require.Equal(t, map[string][]ChangelogEntry{
+		"v1": {
+			{Kind: ElementMessage, FullName: "test.Thing", File: "changelog.proto"},
+			{Kind: ElementService, FullName: "test.ThingService", File: "changelog.proto"},
+		},
+		"v2": {
+			{Kind: ElementField, FullName: "test.Thing.color", File: "changelog.proto"},
+			{Kind: ElementEnumValue, FullName: "test.Kind.KIND_LARGE", File: "changelog.proto"},
+			{Kind: ElementMethod, FullName: "test.ThingService.Paint", File: "changelog.proto"},
+		},
+	}, tmpl.Changelog())
+
+	require.Equal(t, "A thing. ", findMessage("Thing", tmpl.Files[0]).Description)
+	require.Empty(t, new(Template).Changelog())
+}
+
+func TestSinceDirective(t *testing.T) {
+	directive := &Directive{Descrition: "A thing.\n@since v1.2 (beta)"}
+	require.Equal(t, "v1.2", directive.Since())
+	require.Equal(t, "A thing.\n (beta)", directive.Descrition)
+
+	directive = &Directive{Descrition: "Seconds @since_epoch."}
+	require.Empty(t, directive.Since())
+	require.Equal(t, "Seconds @since_epoch.", directive.Descrition)
+}
@@ -0,0 +1,50 @@
+package gendoc
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strconv"
+)
+
+// csvHeader lists the columns written by Template.ToCSV.
+var csvHeader = []string{"file", "message", "field", "number", "type", "label", "required", "deprecated", "description"}
+
+// ToCSV returns every field in the template as CSV, one row per field preceded by a header row. The columns are the
+// file name, the full name of the message, the field name, number, type, label, whether the field is required or
+// deprecated, and its description. Excluded files and messages are left out, as are the entry messages of map fields.
+func (t *Template) ToCSV() string {
+	buf := new(bytes.Buffer)
+	w := csv.NewWriter(buf)
+	_ = w.Write(csvHeader)
+
+	for _, f := range t.Files {
+		if f.Exclude {
+			continue
+		}
+
+		mapEntries := mapEntryNames(f)
+		for _, m := range f.Messages {
+			if m.Exclude || mapEntries[m.FullName] {
+				continue
+			}
+
+			for _, field := range m.Fields {
+				deprecated, _ := field.Options["deprecated"].(bool)
+				_ = w.Write([]string{
+					f.Name,
+					m.FullName,
+					field.Name,
+					strconv.Itoa(field.Number),
+					field.LongType,
+					field.Label,
+					strconv.FormatBool(field.Required),
+					strconv.FormatBool(deprecated),
+					field.Description,
+				})
+			}
+		}
+	}
+
+	w.Flush()
+	return buf.String()
+}
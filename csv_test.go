@@ -0,0 +1,57 @@
+package gendoc_test
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToCSV(t *testing.T) {
+	legacy := newTestField("legacy", 2, descriptor.FieldDescriptorProto_TYPE_INT32, "")
+	legacy.Options = &descriptor.FieldOptions{Deprecated: proto.Bool(true)}
+
+	tags := newTestField("tags", 3, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".test.Thing.TagsEntry")
+	tags.Label = descriptor.FieldDescriptorProto_LABEL_REPEATED.Enum()
+
+	tmpl := newTestTemplate(&descriptor.FileDescriptorProto{
+		Name:    proto.String("csv.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptor.DescriptorProto{
+			{
+				Name: proto.String("Thing"),
+				Field: []*descriptor.FieldDescriptorProto{
+					newTestField("name", 1, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+					legacy,
+					tags,
+				},
+				NestedType: []*descriptor.DescriptorProto{
+					newTestMapEntry("TagsEntry", newTestField("value", 2, descriptor.FieldDescriptorProto_TYPE_STRING, "")),
+				},
+			},
+			{
+				Name:  proto.String("Hidden"),
+				Field: []*descriptor.FieldDescriptorProto{newTestField("id", 1, descriptor.FieldDescriptorProto_TYPE_STRING, "")},
+			},
+		},
+		SourceCodeInfo: &descriptor.SourceCodeInfo{
+			Location: []*descriptor.SourceCodeInfo_Location{
+				{Path: []int32{4, 0, 2, 0}, LeadingComments: proto.String(" The name, e.g. \"thing\".\n Must be unique.\n")},
+				{Path: []int32{4, 1}, LeadingComments: proto.String(" @exclude\n")},
+			},
+		},
+	})
+
+	rows, err := csv.NewReader(strings.NewReader(tmpl.ToCSV())).ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, [][]string{
+		{"file", "message", "field", "number", "type", "label", "required", "deprecated", "description"},
+		{"csv.proto", "test.Thing", "name", "1", "string", "", "false", "false", "The name, e.g. \"thing\".\nMust be unique."},
+		{"csv.proto", "test.Thing", "legacy", "2", "int32", "", "false", "true", ""},
+		{"csv.proto", "test.Thing", "tags", "3", "Thing.TagsEntry", "repeated", "false", "false", ""},
+	}, rows)
+}
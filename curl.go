@@ -0,0 +1,144 @@
+package gendoc
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// httpOptionName is the name of the (google.api.http) method option.
+const httpOptionName = "google.api.http"
+
+var pathParamRegex = regexp.MustCompile(`\{([^}=]+)`)
+
+// httpRule mirrors the JSON shape of the rules produced by the google.api.http extension transformer, which avoids
+// depending on (and registering) the extension from here.
+type httpRule struct {
+	Method  string `json:"method"`
+	Pattern string `json:"pattern"`
+	Body    string `json:"body"`
+}
+
+// httpRules returns the HTTP bindings of the method, or nil if it doesn't have any.
+func (m ServiceMethod) httpRules() []httpRule {
+	opt, ok := m.Options[httpOptionName]
+	if !ok {
+		return nil
+	}
+
+	data, err := json.Marshal(opt)
+	if err != nil {
+		return nil
+	}
+
+	var ext struct {
+		Rules []httpRule `json:"rules"`
+	}
+	if err := json.Unmarshal(data, &ext); err != nil {
+		return nil
+	}
+
+	return ext.Rules
+}
+
+// CurlExample returns a curl command invoking the method through its (first) HTTP binding, e.g.
+//
+//	curl -X POST 'https://api.example.com/v1/things' -H 'Content-Type: application/json' -d '{"name":""}'
+//
+// The JSON body is a skeleton built from RequestFields, leaving out the fields bound to path parameters and those
+// marked with `@no_schema`. When the body is a single field that's unknown (or marked with `@no_schema`), the body is
+// left out. Methods without HTTP bindings return an empty string.
+func (m ServiceMethod) CurlExample(baseURL string) string {
+	rules := m.httpRules()
+	if len(rules) == 0 {
+		return ""
+	}
+
+	rule := rules[0]
+	cmd := fmt.Sprintf("curl -X %s %s", rule.Method, shellQuote(strings.TrimSuffix(baseURL, "/")+rule.Pattern))
+	if rule.Body == "" {
+		return cmd
+	}
+
+	pathParams := make(map[string]bool)
+	for _, match := range pathParamRegex.FindAllStringSubmatch(rule.Pattern, -1) {
+		pathParams[match[1]] = true
+	}
+
+	var body string
+	if rule.Body == "*" {
+		var fields []*InlineField
+		for _, field := range m.RequestFields {
+			if !pathParams[field.Name] {
+				fields = append(fields, field)
+			}
+		}
+		body = jsonSkeleton(fields)
+	} else {
+		for _, field := range m.RequestFields {
+			if field.Name == rule.Body && !field.NoSchema {
+				body = jsonSkeletonValue(field)
+			}
+		}
+	}
+	if body == "" {
+		return cmd
+	}
+
+	return fmt.Sprintf("%s -H 'Content-Type: application/json' -d %s", cmd, shellQuote(body))
+}
+
+// jsonSkeleton returns a JSON object with a placeholder value for each field, in field order. Fields marked with
+// `@no_schema` are left out.
+func jsonSkeleton(fields []*InlineField) string {
+	parts := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if field.NoSchema {
+			continue
+		}
+
+		name := field.JSONName
+		if name == "" {
+			name = field.Name
+		}
+		parts = append(parts, fmt.Sprintf("%q:%s", name, jsonSkeletonValue(field)))
+	}
+
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func jsonSkeletonValue(field *InlineField) string {
+	switch {
+	case field.IsMap:
+		return "{}"
+	case field.Label == "repeated":
+		return "[]"
+	}
+
+	switch field.TypeKind {
+	case typeKindMessage:
+		return jsonSkeleton(field.Fields)
+	case typeKindEnum:
+		if len(field.EnumValues) > 0 {
+			return fmt.Sprintf("%q", field.EnumValues[0].Name)
+		}
+		return `""`
+	}
+
+	switch field.Type {
+	case "bool":
+		return "false"
+	case "string", "bytes":
+		return `""`
+	case "int64", "uint64", "sint64", "fixed64", "sfixed64":
+		// 64 bit integers are strings in the JSON mapping
+		return `"0"`
+	}
+
+	return "0"
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,64 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	. "github.com/pseudomuto/protoc-gen-doc"
+	httpext "github.com/pseudomuto/protoc-gen-doc/extensions/google_api_http"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCurlExample(t *testing.T) {
+	fields := []*InlineField{
+		{MessageField: &MessageField{Name: "id", JSONName: "id", Type: "int64", TypeKind: "scalar"}},
+		{MessageField: &MessageField{Name: "display_name", JSONName: "displayName", Type: "string", TypeKind: "scalar"}},
+		{MessageField: &MessageField{Name: "tags", JSONName: "tags", Label: "repeated", Type: "string", TypeKind: "scalar"}},
+		{MessageField: &MessageField{Name: "etag", JSONName: "etag", Type: "string", TypeKind: "scalar", NoSchema: true}},
+		{
+			MessageField: &MessageField{Name: "owner", JSONName: "owner", TypeKind: "message"},
+			Fields: []*InlineField{
+				{MessageField: &MessageField{Name: "active", JSONName: "active", Type: "bool", TypeKind: "scalar"}},
+			},
+		},
+	}
+
+	method := ServiceMethod{
+		RequestFields: fields,
+		Options: map[string]interface{}{
+			"google.api.http": httpext.HTTPExtension{Rules: []httpext.HTTPRule{
+				{Method: "PATCH", Pattern: "/v1/things/{id}", Body: "*"},
+			}},
+		},
+	}
+	require.Equal(t,
+		`curl -X PATCH 'https://api.example.com/v1/things/{id}' -H 'Content-Type: application/json' `+
+			`-d '{"displayName":"","tags":[],"owner":{"active":false}}'`,
+		method.CurlExample("https://api.example.com/"),
+	)
+
+	method.Options["google.api.http"] = httpext.HTTPExtension{Rules: []httpext.HTTPRule{
+		{Method: "PUT", Pattern: "/v1/things/{id}/owner", Body: "owner"},
+	}}
+	require.Equal(t,
+		`curl -X PUT 'https://api.example.com/v1/things/{id}/owner' -H 'Content-Type: application/json' -d '{"active":false}'`,
+		method.CurlExample("https://api.example.com"),
+	)
+
+	for _, body := range []string{"etag", "unknown"} {
+		method.Options["google.api.http"] = httpext.HTTPExtension{Rules: []httpext.HTTPRule{
+			{Method: "PUT", Pattern: "/v1/things/{id}/" + body, Body: body},
+		}}
+		require.Equal(t,
+			`curl -X PUT 'https://api.example.com/v1/things/{id}/`+body+`'`,
+			method.CurlExample("https://api.example.com"),
+			body,
+		)
+	}
+
+	method.Options["google.api.http"] = httpext.HTTPExtension{Rules: []httpext.HTTPRule{
+		{Method: "GET", Pattern: "/v1/things/{id}"},
+	}}
+	require.Equal(t, `curl -X GET 'https://api.example.com/v1/things/{id}'`, method.CurlExample("https://api.example.com"))
+
+	require.Empty(t, ServiceMethod{}.CurlExample("https://api.example.com"))
+}
@@ -0,0 +1,106 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+	. "github.com/pseudomuto/protoc-gen-doc"
+	"github.com/stretchr/testify/require"
+)
+
+func describedFile() *descriptor.FileDescriptorProto {
+	return &descriptor.FileDescriptorProto{
+		Name:    proto.String("described.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptor.DescriptorProto{
+			{
+				Name: proto.String("Thing"),
+				Field: []*descriptor.FieldDescriptorProto{
+					newTestField("name", 1, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+					newTestField("size", 2, descriptor.FieldDescriptorProto_TYPE_INT32, ""),
+				},
+			},
+		},
+		SourceCodeInfo: &descriptor.SourceCodeInfo{
+			Location: []*descriptor.SourceCodeInfo_Location{
+				{Path: []int32{4, 0}, LeadingComments: proto.String(" A thing.\n")},
+				{Path: []int32{4, 0, 2, 1}, LeadingComments: proto.String(" @readonly\n")},
+			},
+		},
+	}
+}
+
+func TestHasDescription(t *testing.T) {
+	thing := findMessage("Thing", newTestTemplate(describedFile()).Files[0])
+	require.True(t, thing.HasDescription)
+	require.False(t, findField("name", thing).HasDescription)
+	require.Empty(t, findField("name", thing).Description)
+	require.False(t, findField("size", thing).HasDescription)
+
+	require.True(t, findMessage("Vehicle", vehicleFile).HasDescription)
+	require.True(t, findService("VehicleService", vehicleFile).HasDescription)
+}
+
+func TestDefaultDescription(t *testing.T) {
+	tmpl := newTestTemplateWithOptions(TemplateOptions{DefaultDescription: "Not documented."}, describedFile())
+
+	file := tmpl.Files[0]
+	require.False(t, file.HasDescription)
+	require.Equal(t, "Not documented.", file.Description)
+
+	thing := findMessage("Thing", file)
+	require.Equal(t, "A thing.", thing.Description)
+	require.False(t, findField("size", thing).HasDescription)
+	require.Equal(t, "Not documented.", findField("size", thing).Description)
+
+	require.Equal(t, []string{"test.Thing.name", "test.Thing.size"}, tmpl.Undocumented())
+}
+
+func TestCommentPrefix(t *testing.T) {
+	fd := &descriptor.FileDescriptorProto{
+		Name:    proto.String("prefixed.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptor.DescriptorProto{
+			{
+				Name: proto.String("Thing"),
+				Field: []*descriptor.FieldDescriptorProto{
+					newTestField("name", 1, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+					newTestField("size", 2, descriptor.FieldDescriptorProto_TYPE_INT32, ""),
+				},
+			},
+		},
+		SourceCodeInfo: &descriptor.SourceCodeInfo{
+			Location: []*descriptor.SourceCodeInfo_Location{
+				{
+					Path:                    []int32{4, 0},
+					LeadingDetachedComments: []string{" DOC: Things.\n", " TODO: refactor\n"},
+					LeadingComments:         proto.String(" DOC: A thing.\n It has a name.\n"),
+				},
+				{Path: []int32{4, 0, 2, 0}, LeadingComments: proto.String(" DOC:The name. @readonly\n")},
+				{Path: []int32{4, 0, 2, 1}, LeadingComments: proto.String(" Not for the docs. @readonly\n")},
+			},
+		},
+	}
+
+	tmpl := newTestTemplateWithOptions(TemplateOptions{CommentPrefix: "DOC:"}, fd)
+
+	thing := findMessage("Thing", tmpl.Files[0])
+	require.Equal(t, "A thing.\nIt has a name.", thing.Description)
+	require.Equal(t, []string{"Things."}, thing.DetachedComments)
+
+	name := findField("name", thing)
+	require.Equal(t, "The name. ", name.Description)
+	require.True(t, name.ReadOnly)
+
+	size := findField("size", thing)
+	require.Empty(t, size.Description)
+	require.False(t, size.HasDescription)
+	require.False(t, size.ReadOnly)
+
+	thing = findMessage("Thing", newTestTemplate(fd).Files[0])
+	require.Equal(t, "DOC: A thing.\nIt has a name.", thing.Description)
+	require.Equal(t, "Not for the docs. ", findField("size", thing).Description)
+}
@@ -0,0 +1,26 @@
+package gendoc
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+	plugin_go "github.com/golang/protobuf/protoc-gen-go/plugin"
+	"github.com/pseudomuto/protokit"
+)
+
+// NewTemplateFromDescriptorSet creates a Template from an encoded FileDescriptorSet, e.g. the output of
+// `protoc --descriptor_set_out=... --include_source_info`. All files in the set are included.
+func NewTemplateFromDescriptorSet(data []byte) (*Template, error) {
+	set := new(descriptor.FileDescriptorSet)
+	if err := proto.Unmarshal(data, set); err != nil {
+		return nil, fmt.Errorf("Invalid FileDescriptorSet: %v", err)
+	}
+
+	req := &plugin_go.CodeGeneratorRequest{ProtoFile: set.GetFile()}
+	for _, f := range set.GetFile() {
+		req.FileToGenerate = append(req.FileToGenerate, f.GetName())
+	}
+
+	return NewTemplate(protokit.ParseCodeGenRequest(req)), nil
+}
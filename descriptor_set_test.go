@@ -0,0 +1,29 @@
+package gendoc_test
+
+import (
+	"io/ioutil"
+	"testing"
+
+	. "github.com/pseudomuto/protoc-gen-doc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTemplateFromDescriptorSet(t *testing.T) {
+	data, err := ioutil.ReadFile("fixtures/fileset.pb")
+	require.NoError(t, err)
+
+	tmpl, err := NewTemplateFromDescriptorSet(data)
+	require.NoError(t, err)
+
+	var names []string
+	for _, f := range tmpl.Files {
+		names = append(names, f.Name)
+	}
+	require.Contains(t, names, "Booking.proto")
+	require.Contains(t, names, "Vehicle.proto")
+	require.NotNil(t, findMessage("Vehicle", tmpl.Files[len(tmpl.Files)-1]))
+
+	_, err = NewTemplateFromDescriptorSet([]byte("not a descriptor set"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Invalid FileDescriptorSet")
+}
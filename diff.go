@@ -0,0 +1,222 @@
+package gendoc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ChangeKind describes how an element changed between two Templates.
+type ChangeKind string
+
+// Available change kinds.
+const (
+	ChangeAdded   ChangeKind = "added"
+	ChangeRemoved ChangeKind = "removed"
+	ChangeChanged ChangeKind = "changed"
+)
+
+// Change describes a single difference between two Templates. Element is one of "message", "field", "enum",
+// "enum value", "service", or "method". Before and After hold a short description of the element in the old and new
+// Template respectively (empty when the element doesn't exist on that side).
+type Change struct {
+	Kind     ChangeKind `json:"kind"`
+	Element  string     `json:"element"`
+	FullName string     `json:"fullName"`
+	Before   string     `json:"before"`
+	After    string     `json:"after"`
+}
+
+// Diff compares two Templates and reports the messages, fields, enums, enum values, services, and methods that were
+// added, removed, or changed. Changes are sorted by full name.
+//
+// Fields are matched by name and are considered changed when their label, type, or number differs. Enum values are
+// matched by name and compared by number. Methods are matched by name and compared by their request/response types
+// and streaming flags. A nil Template is treated as an empty one (e.g. when an API was added or removed entirely).
+func Diff(from, to *Template) []*Change {
+	if from == nil {
+		from = new(Template)
+	}
+	if to == nil {
+		to = new(Template)
+	}
+
+	var changes []*Change
+	add := func(kind ChangeKind, element, fullName, before, after string) {
+		changes = append(changes, &Change{Kind: kind, Element: element, FullName: fullName, Before: before, After: after})
+	}
+
+	oldMessages, newMessages := messagesByName(from), messagesByName(to)
+	for name, om := range oldMessages {
+		nm, ok := newMessages[name]
+		if !ok {
+			add(ChangeRemoved, "message", name, name, "")
+			continue
+		}
+
+		oldFields, newFields := fieldsByName(om), fieldsByName(nm)
+		for fieldName, of := range oldFields {
+			fullName := name + "." + fieldName
+			if nf, ok := newFields[fieldName]; !ok {
+				add(ChangeRemoved, "field", fullName, describeField(of), "")
+			} else if before, after := describeField(of), describeField(nf); before != after {
+				add(ChangeChanged, "field", fullName, before, after)
+			}
+		}
+		for fieldName, nf := range newFields {
+			if _, ok := oldFields[fieldName]; !ok {
+				add(ChangeAdded, "field", name+"."+fieldName, "", describeField(nf))
+			}
+		}
+	}
+	for name := range newMessages {
+		if _, ok := oldMessages[name]; !ok {
+			add(ChangeAdded, "message", name, "", name)
+		}
+	}
+
+	oldEnums, newEnums := enumsByName(from), enumsByName(to)
+	for name, oe := range oldEnums {
+		ne, ok := newEnums[name]
+		if !ok {
+			add(ChangeRemoved, "enum", name, name, "")
+			continue
+		}
+
+		oldValues, newValues := valuesByName(oe), valuesByName(ne)
+		for valueName, ov := range oldValues {
+			fullName := name + "." + valueName
+			if nv, ok := newValues[valueName]; !ok {
+				add(ChangeRemoved, "enum value", fullName, ov.Number, "")
+			} else if ov.Number != nv.Number {
+				add(ChangeChanged, "enum value", fullName, ov.Number, nv.Number)
+			}
+		}
+		for valueName, nv := range newValues {
+			if _, ok := oldValues[valueName]; !ok {
+				add(ChangeAdded, "enum value", name+"."+valueName, "", nv.Number)
+			}
+		}
+	}
+	for name := range newEnums {
+		if _, ok := oldEnums[name]; !ok {
+			add(ChangeAdded, "enum", name, "", name)
+		}
+	}
+
+	oldServices, newServices := servicesByName(from), servicesByName(to)
+	for name, oldService := range oldServices {
+		ns, ok := newServices[name]
+		if !ok {
+			add(ChangeRemoved, "service", name, name, "")
+			continue
+		}
+
+		oldMethods, newMethods := methodsByName(oldService), methodsByName(ns)
+		for methodName, om := range oldMethods {
+			fullName := name + "." + methodName
+			if nm, ok := newMethods[methodName]; !ok {
+				add(ChangeRemoved, "method", fullName, describeMethod(om), "")
+			} else if before, after := describeMethod(om), describeMethod(nm); before != after {
+				add(ChangeChanged, "method", fullName, before, after)
+			}
+		}
+		for methodName, nm := range newMethods {
+			if _, ok := oldMethods[methodName]; !ok {
+				add(ChangeAdded, "method", name+"."+methodName, "", describeMethod(nm))
+			}
+		}
+	}
+	for name := range newServices {
+		if _, ok := oldServices[name]; !ok {
+			add(ChangeAdded, "service", name, "", name)
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].FullName != changes[j].FullName {
+			return changes[i].FullName < changes[j].FullName
+		}
+		return changes[i].Element < changes[j].Element
+	})
+
+	return changes
+}
+
+func messagesByName(t *Template) map[string]*Message {
+	out := make(map[string]*Message)
+	for _, f := range t.Files {
+		for _, m := range f.Messages {
+			out[m.FullName] = m
+		}
+	}
+	return out
+}
+
+func fieldsByName(m *Message) map[string]*MessageField {
+	out := make(map[string]*MessageField, len(m.Fields))
+	for _, f := range m.Fields {
+		out[f.Name] = f
+	}
+	return out
+}
+
+func enumsByName(t *Template) map[string]*Enum {
+	out := make(map[string]*Enum)
+	for _, f := range t.Files {
+		for _, e := range f.Enums {
+			out[e.FullName] = e
+		}
+	}
+	return out
+}
+
+func valuesByName(e *Enum) map[string]*EnumValue {
+	out := make(map[string]*EnumValue, len(e.Values))
+	for _, v := range e.Values {
+		out[v.Name] = v
+	}
+	return out
+}
+
+func servicesByName(t *Template) map[string]*Service {
+	out := make(map[string]*Service)
+	for _, f := range t.Files {
+		for _, s := range f.Services {
+			out[s.FullName] = s
+		}
+	}
+	return out
+}
+
+func methodsByName(s *Service) map[string]*ServiceMethod {
+	out := make(map[string]*ServiceMethod, len(s.Methods))
+	for _, m := range s.Methods {
+		out[m.Name] = m
+	}
+	return out
+}
+
+// describeField formats a field like its declaration, e.g. "repeated string tags = 3".
+func describeField(f *MessageField) string {
+	return strings.TrimSpace(fmt.Sprintf("%s %s %s = %d", f.Label, f.FullType, f.Name, f.Number))
+}
+
+// describeMethod formats a method like its declaration, e.g. "GetVehicle(FindVehicleById) returns (stream Vehicle)".
+func describeMethod(m *ServiceMethod) string {
+	stream := func(streaming bool) string {
+		if streaming {
+			return "stream "
+		}
+		return ""
+	}
+
+	return fmt.Sprintf(
+		"%s(%s%s) returns (%s%s)",
+		m.Name,
+		stream(m.RequestStreaming),
+		m.RequestFullType,
+		stream(m.ResponseStreaming),
+		m.ResponseFullType,
+	)
+}
@@ -0,0 +1,76 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	. "github.com/pseudomuto/protoc-gen-doc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffIdenticalTemplates(t *testing.T) {
+	require.Empty(t, Diff(template, template))
+}
+
+func TestDiffWithNilTemplates(t *testing.T) {
+	tmpl := &Template{Files: []*File{{Messages: []*Message{{FullName: "test.User"}}}}}
+
+	require.Equal(t, []*Change{{Kind: ChangeAdded, Element: "message", FullName: "test.User", After: "test.User"}}, Diff(nil, tmpl))
+	require.Equal(t, []*Change{{Kind: ChangeRemoved, Element: "message", FullName: "test.User", Before: "test.User"}}, Diff(tmpl, nil))
+	require.Empty(t, Diff(nil, nil))
+}
+
+func TestDiff(t *testing.T) {
+	from := &Template{Files: []*File{{
+		Messages: []*Message{
+			{FullName: "test.User", Fields: []*MessageField{
+				{Name: "id", FullType: "string", Number: 1},
+				{Name: "email", FullType: "string", Number: 2},
+				{Name: "age", FullType: "int32", Number: 3},
+			}},
+			{FullName: "test.Legacy"},
+		},
+		Enums: []*Enum{{FullName: "test.Role", Values: []*EnumValue{
+			{Name: "ADMIN", Number: "0"},
+			{Name: "GUEST", Number: "1"},
+		}}},
+		Services: []*Service{{FullName: "test.UserService", Methods: []*ServiceMethod{
+			{Name: "GetUser", RequestFullType: "test.GetUserRequest", ResponseFullType: "test.User"},
+			{Name: "DeleteUser", RequestFullType: "test.User", ResponseFullType: "test.User"},
+		}}},
+	}}}
+
+	to := &Template{Files: []*File{{
+		Messages: []*Message{
+			{FullName: "test.User", Fields: []*MessageField{
+				{Name: "id", FullType: "string", Number: 1},
+				{Name: "age", FullType: "int64", Number: 3},
+				{Name: "tags", Label: "repeated", FullType: "string", Number: 4},
+			}},
+			{FullName: "test.Group"},
+		},
+		Enums: []*Enum{{FullName: "test.Role", Values: []*EnumValue{
+			{Name: "ADMIN", Number: "0"},
+			{Name: "GUEST", Number: "2"},
+		}}},
+		Services: []*Service{{FullName: "test.UserService", Methods: []*ServiceMethod{
+			{Name: "GetUser", RequestFullType: "test.GetUserRequest", ResponseFullType: "test.User", ResponseStreaming: true},
+		}}},
+	}}}
+
+	require.Equal(t, []*Change{
+		{Kind: ChangeAdded, Element: "message", FullName: "test.Group", After: "test.Group"},
+		{Kind: ChangeRemoved, Element: "message", FullName: "test.Legacy", Before: "test.Legacy"},
+		{Kind: ChangeChanged, Element: "enum value", FullName: "test.Role.GUEST", Before: "1", After: "2"},
+		{Kind: ChangeChanged, Element: "field", FullName: "test.User.age", Before: "int32 age = 3", After: "int64 age = 3"},
+		{Kind: ChangeRemoved, Element: "field", FullName: "test.User.email", Before: "string email = 2"},
+		{Kind: ChangeAdded, Element: "field", FullName: "test.User.tags", After: "repeated string tags = 4"},
+		{Kind: ChangeRemoved, Element: "method", FullName: "test.UserService.DeleteUser", Before: "DeleteUser(test.User) returns (test.User)"},
+		{
+			Kind:     ChangeChanged,
+			Element:  "method",
+			FullName: "test.UserService.GetUser",
+			Before:   "GetUser(test.GetUserRequest) returns (test.User)",
+			After:    "GetUser(test.GetUserRequest) returns (stream test.User)",
+		},
+	}, Diff(from, to))
+}
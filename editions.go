@@ -0,0 +1,188 @@
+package gendoc
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+	"github.com/pseudomuto/protokit"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+// The descriptor types used by this package predate protobuf editions, so the editions related fields of
+// descriptor.proto end up in the unknown fields of the descriptors. These are their field numbers.
+const (
+	fileEditionField                  protowire.Number = 14 // FileDescriptorProto.edition
+	optionsFeaturesField              protowire.Number = 50 // FileOptions.features, MessageOptions.features, etc.
+	featureFieldPresenceField         protowire.Number = 1  // FeatureSet.field_presence
+	featureRepeatedFieldEncodingField protowire.Number = 3  // FeatureSet.repeated_field_encoding
+)
+
+// Values of the FeatureSet.FieldPresence enum.
+const (
+	fieldPresenceExplicit       = 1
+	fieldPresenceImplicit       = 2
+	fieldPresenceLegacyRequired = 3
+)
+
+// Values of the FeatureSet.RepeatedFieldEncoding enum.
+const (
+	repeatedFieldEncodingPacked   = 1
+	repeatedFieldEncodingExpanded = 2
+)
+
+// Names of the well-known values of the Edition enum.
+var editionNames = map[uint64]string{
+	998:  "proto2",
+	999:  "proto3",
+	1000: "2023",
+	1001: "2024",
+}
+
+// fileEdition returns the edition of the file (e.g. "2023"), or "" for files using proto2/proto3 syntax.
+func fileEdition(fd *descriptor.FileDescriptorProto) string {
+	edition, ok := unknownVarint(fd, fileEditionField)
+	if !ok {
+		return ""
+	}
+
+	if name, ok := editionNames[edition]; ok {
+		return name
+	}
+
+	return fmt.Sprint(edition)
+}
+
+// isEditionsFile reports whether the file uses editions rather than proto2/proto3 syntax.
+func isEditionsFile(f *protokit.FileDescriptor) bool {
+	return f.GetSyntax() == "editions"
+}
+
+// editionLabelName returns the label of a field defined in an editions file. Editions don't have the optional and
+// required keywords, instead presence is controlled by the field_presence feature which can be set on the field, its
+// enclosing messages, or the file (explicit presence is the default).
+func editionLabelName(pf *protokit.FieldDescriptor) string {
+	if pf.GetLabel() == descriptor.FieldDescriptorProto_LABEL_REPEATED {
+		return "repeated"
+	}
+
+	switch fieldPresence(pf) {
+	case fieldPresenceImplicit:
+		return ""
+	case fieldPresenceLegacyRequired:
+		return "required"
+	}
+
+	return "optional"
+}
+
+// editionPacked reports whether a repeated scalar field defined in an editions file is packed. Editions don't have the
+// packed option, instead it's controlled by the repeated_field_encoding feature (packed is the default).
+func editionPacked(pf *protokit.FieldDescriptor) bool {
+	encoding, ok := fieldFeature(pf, featureRepeatedFieldEncodingField)
+	return !ok || encoding == repeatedFieldEncodingPacked
+}
+
+func fieldPresence(pf *protokit.FieldDescriptor) uint64 {
+	if presence, ok := fieldFeature(pf, featureFieldPresenceField); ok {
+		return presence
+	}
+
+	return fieldPresenceExplicit
+}
+
+// fieldFeature returns the value of the given feature for the field. Features set on the field override those of its
+// enclosing messages, which override those of the file.
+func fieldFeature(pf *protokit.FieldDescriptor, num protowire.Number) (uint64, bool) {
+	if value, ok := feature(pf.GetOptions(), num); ok {
+		return value, true
+	}
+
+	for msg := pf.GetMessage(); msg != nil; msg = msg.GetParent() {
+		if value, ok := feature(msg.GetOptions(), num); ok {
+			return value, true
+		}
+	}
+
+	if file := pf.GetFile(); file != nil {
+		if value, ok := feature(file.GetOptions(), num); ok {
+			return value, true
+		}
+	}
+
+	return 0, false
+}
+
+func feature(opts proto.Message, num protowire.Number) (uint64, bool) {
+	if opts == nil || !opts.ProtoReflect().IsValid() {
+		return 0, false
+	}
+
+	features, ok := unknownBytes(opts.ProtoReflect().GetUnknown(), optionsFeaturesField)
+	if !ok {
+		return 0, false
+	}
+
+	return consumeVarintField(features, num)
+}
+
+func unknownVarint(m proto.Message, num protowire.Number) (uint64, bool) {
+	return consumeVarintField(m.ProtoReflect().GetUnknown(), num)
+}
+
+// consumeVarintField returns the last value of the varint field with the given number in the encoded message.
+func consumeVarintField(b []byte, num protowire.Number) (value uint64, found bool) {
+	for len(b) > 0 {
+		n, typ, tagLen := protowire.ConsumeTag(b)
+		if tagLen < 0 {
+			return value, found
+		}
+		b = b[tagLen:]
+
+		if n == num && typ == protowire.VarintType {
+			v, vLen := protowire.ConsumeVarint(b)
+			if vLen < 0 {
+				return value, found
+			}
+			value, found = v, true
+			b = b[vLen:]
+			continue
+		}
+
+		valLen := protowire.ConsumeFieldValue(n, typ, b)
+		if valLen < 0 {
+			return value, found
+		}
+		b = b[valLen:]
+	}
+
+	return value, found
+}
+
+// unknownBytes returns the (merged) contents of the length-delimited field with the given number.
+func unknownBytes(b []byte, num protowire.Number) ([]byte, bool) {
+	var out []byte
+	found := false
+
+	for len(b) > 0 {
+		n, typ, tagLen := protowire.ConsumeTag(b)
+		if tagLen < 0 {
+			break
+		}
+		b = b[tagLen:]
+
+		valLen := protowire.ConsumeFieldValue(n, typ, b)
+		if valLen < 0 {
+			break
+		}
+
+		if n == num && typ == protowire.BytesType {
+			v, _ := protowire.ConsumeBytes(b[:valLen])
+			out = append(out, v...)
+			found = true
+		}
+		b = b[valLen:]
+	}
+
+	return out, found
+}
@@ -0,0 +1,129 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// withFieldPresence sets features.field_presence on the given options, which this descriptor version doesn't know.
+func withFieldPresence(opts proto.Message, presence uint64) {
+	withFeature(opts, 1, presence)
+}
+
+// withFeature sets the given (varint) feature on the options.
+func withFeature(opts proto.Message, num protowire.Number, value uint64) {
+	features := protowire.AppendTag(nil, num, protowire.VarintType)
+	features = protowire.AppendVarint(features, value)
+
+	raw := protowire.AppendTag(nil, 50, protowire.BytesType)
+	raw = protowire.AppendBytes(raw, features)
+	proto.MessageReflect(opts).SetUnknown(raw)
+}
+
+func TestEditions(t *testing.T) {
+	implicit := newTestField("implicit", 2, descriptor.FieldDescriptorProto_TYPE_STRING, "")
+	implicit.Options = new(descriptor.FieldOptions)
+	withFieldPresence(implicit.Options, 2)
+
+	required := newTestField("required", 3, descriptor.FieldDescriptorProto_TYPE_STRING, "")
+	required.Options = new(descriptor.FieldOptions)
+	withFieldPresence(required.Options, 3)
+
+	repeated := newTestField("repeated", 4, descriptor.FieldDescriptorProto_TYPE_STRING, "")
+	repeated.Label = descriptor.FieldDescriptorProto_LABEL_REPEATED.Enum()
+
+	inherited := newTestField("inherited", 1, descriptor.FieldDescriptorProto_TYPE_STRING, "")
+	implicitMessage := &descriptor.DescriptorProto{
+		Name:    proto.String("Implicit"),
+		Field:   []*descriptor.FieldDescriptorProto{inherited},
+		Options: new(descriptor.MessageOptions),
+	}
+	withFieldPresence(implicitMessage.Options, 2)
+
+	fd := &descriptor.FileDescriptorProto{
+		Name:    proto.String("editions.proto"),
+		Package: proto.String("com.example"),
+		Syntax:  proto.String("editions"),
+		MessageType: []*descriptor.DescriptorProto{
+			{
+				Name: proto.String("Thing"),
+				Field: []*descriptor.FieldDescriptorProto{
+					newTestField("explicit", 1, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+					implicit,
+					required,
+					repeated,
+				},
+			},
+			implicitMessage,
+		},
+	}
+	edition := protowire.AppendTag(nil, 14, protowire.VarintType)
+	proto.MessageReflect(fd).SetUnknown(protowire.AppendVarint(edition, 1000))
+
+	file := newTestTemplate(fd).Files[0]
+	require.Equal(t, "editions", file.Syntax)
+	require.Equal(t, "2023", file.Edition)
+
+	thing := findMessage("Thing", file)
+	require.Equal(t, "optional", findField("explicit", thing).Label)
+	require.Equal(t, "", findField("implicit", thing).Label)
+	require.Equal(t, "required", findField("required", thing).Label)
+	require.True(t, findField("required", thing).Required)
+	require.Equal(t, "repeated", findField("repeated", thing).Label)
+
+	require.Equal(t, "", findField("inherited", findMessage("Implicit", file)).Label)
+}
+
+func TestEditionIsEmptyForSyntaxFiles(t *testing.T) {
+	file := newTestTemplate(&descriptor.FileDescriptorProto{
+		Name:   proto.String("proto3.proto"),
+		Syntax: proto.String("proto3"),
+	}).Files[0]
+
+	require.Equal(t, "proto3", file.Syntax)
+	require.Empty(t, file.Edition)
+}
+
+func TestEditionsPackedFields(t *testing.T) {
+	repeated := func(name string, number int32) *descriptor.FieldDescriptorProto {
+		field := newTestField(name, number, descriptor.FieldDescriptorProto_TYPE_INT32, "")
+		field.Label = descriptor.FieldDescriptorProto_LABEL_REPEATED.Enum()
+		return field
+	}
+
+	expanded := repeated("expanded", 2)
+	expanded.Options = new(descriptor.FieldOptions)
+	withFeature(expanded.Options, 3, 2) // repeated_field_encoding = EXPANDED
+
+	expandedMessage := &descriptor.DescriptorProto{
+		Name:    proto.String("Expanded"),
+		Field:   []*descriptor.FieldDescriptorProto{repeated("ids", 1)},
+		Options: new(descriptor.MessageOptions),
+	}
+	withFeature(expandedMessage.Options, 3, 2)
+
+	fd := &descriptor.FileDescriptorProto{
+		Name:    proto.String("editions.proto"),
+		Package: proto.String("com.example"),
+		Syntax:  proto.String("editions"),
+		MessageType: []*descriptor.DescriptorProto{
+			{
+				Name:  proto.String("Thing"),
+				Field: []*descriptor.FieldDescriptorProto{repeated("ids", 1), expanded},
+			},
+			expandedMessage,
+		},
+	}
+	edition := protowire.AppendTag(nil, 14, protowire.VarintType)
+	proto.MessageReflect(fd).SetUnknown(protowire.AppendVarint(edition, 1000))
+
+	file := newTestTemplate(fd).Files[0]
+	thing := findMessage("Thing", file)
+	require.True(t, findField("ids", thing).Packed)
+	require.False(t, findField("expanded", thing).Packed)
+	require.False(t, findField("ids", findMessage("Expanded", file)).Packed)
+}
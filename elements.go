@@ -0,0 +1,77 @@
+package gendoc
+
+// Kinds of elements listed by Template.AllElements and Template.Changelog (see Element.Kind and ChangelogEntry.Kind).
+const (
+	ElementMessage   = "message"
+	ElementField     = "field"
+	ElementEnum      = "enum"
+	ElementEnumValue = "enum value"
+	ElementService   = "service"
+	ElementMethod    = "method"
+)
+
+// Element is a documented element of any kind, see Template.AllElements. Fields link to the anchor of their message,
+// and methods to that of their service.
+type Element struct {
+	Kind        string `json:"kind"`
+	FullName    string `json:"fullName"`
+	Description string `json:"description"`
+	Anchor      string `json:"anchor"`
+	File        string `json:"file"`
+}
+
+// AllElements returns the messages, fields, enums, enum values, services, and methods of the template as one flat list
+// (e.g. for a search index), in the order of the Template. Elements marked with Exclude (and everything in them) are
+// left out, unless includeExcluded is set.
+func (t *Template) AllElements(includeExcluded bool) []Element {
+	elements := make([]Element, 0)
+	add := func(kind, fullName, description, anchor string, f *File) {
+		elements = append(elements, Element{
+			Kind:        kind,
+			FullName:    fullName,
+			Description: description,
+			Anchor:      anchor,
+			File:        f.Name,
+		})
+	}
+	skip := func(exclude bool) bool { return exclude && !includeExcluded }
+
+	for _, f := range t.Files {
+		if skip(f.Exclude) {
+			continue
+		}
+
+		for _, m := range f.Messages {
+			if skip(m.Exclude) {
+				continue
+			}
+			add(ElementMessage, m.FullName, m.Description, m.Anchor(), f)
+			for _, field := range m.Fields {
+				add(ElementField, m.FullName+"."+field.Name, field.Description, m.Anchor(), f)
+			}
+		}
+		for _, e := range f.Enums {
+			if skip(e.Exclude) {
+				continue
+			}
+			add(ElementEnum, e.FullName, e.Description, e.Anchor(), f)
+			for _, v := range e.Values {
+				add(ElementEnumValue, e.FullName+"."+v.Name, v.Description, e.ValueAnchor(v.Name), f)
+			}
+		}
+		for _, s := range f.Services {
+			if skip(s.Exclude) {
+				continue
+			}
+			add(ElementService, s.FullName, s.Description, s.Anchor(), f)
+			for _, m := range s.Methods {
+				if skip(m.Exclude) {
+					continue
+				}
+				add(ElementMethod, s.FullName+"."+m.Name, m.Description, s.Anchor(), f)
+			}
+		}
+	}
+
+	return elements
+}
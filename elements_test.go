@@ -0,0 +1,66 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/pseudomuto/protoc-gen-doc"
+)
+
+func TestAllElements(t *testing.T) {
+	tmpl := newTestTemplate(&descriptor.FileDescriptorProto{
+		Name:    proto.String("elements.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptor.DescriptorProto{
+			{
+				Name:  proto.String("Thing"),
+				Field: []*descriptor.FieldDescriptorProto{newTestField("id", 1, descriptor.FieldDescriptorProto_TYPE_STRING, "")},
+			},
+			{Name: proto.String("Hidden")},
+		},
+		EnumType: []*descriptor.EnumDescriptorProto{{
+			Name:  proto.String("Kind"),
+			Value: []*descriptor.EnumValueDescriptorProto{{Name: proto.String("KIND_UNSPECIFIED"), Number: proto.Int32(0)}},
+		}},
+		Service: []*descriptor.ServiceDescriptorProto{{
+			Name: proto.String("ThingService"),
+			Method: []*descriptor.MethodDescriptorProto{
+				{Name: proto.String("GetThing"), InputType: proto.String(".test.Thing"), OutputType: proto.String(".test.Thing")},
+				{Name: proto.String("Purge"), InputType: proto.String(".test.Thing"), OutputType: proto.String(".test.Thing")},
+			},
+		}},
+		SourceCodeInfo: &descriptor.SourceCodeInfo{
+			Location: []*descriptor.SourceCodeInfo_Location{
+				{Path: []int32{4, 0}, LeadingComments: proto.String(" A thing.\n")},
+				{Path: []int32{4, 0, 2, 0}, LeadingComments: proto.String(" The id.\n")},
+				{Path: []int32{4, 1}, LeadingComments: proto.String(" @exclude\n")},
+				{Path: []int32{6, 0, 2, 1}, LeadingComments: proto.String(" @exclude\n")},
+			},
+		},
+	})
+
+	require.Equal(t, []Element{
+		{Kind: ElementMessage, FullName: "test.Thing", Description: "A thing.", Anchor: "test.Thing", File: "elements.proto"},
+		{Kind: ElementField, FullName: "test.Thing.id", Description: "The id.", Anchor: "test.Thing", File: "elements.proto"},
+		{Kind: ElementEnum, FullName: "test.Kind", Anchor: "test.Kind", File: "elements.proto"},
+		{
+			Kind:     ElementEnumValue,
+			FullName: "test.Kind.KIND_UNSPECIFIED",
+			Anchor:   "test.Kind.KIND_UNSPECIFIED",
+			File:     "elements.proto",
+		},
+		{Kind: ElementService, FullName: "test.ThingService", Anchor: "test.ThingService", File: "elements.proto"},
+		{Kind: ElementMethod, FullName: "test.ThingService.GetThing", Anchor: "test.ThingService", File: "elements.proto"},
+	}, tmpl.AllElements(false))
+
+	all := tmpl.AllElements(true)
+	require.Len(t, all, 8)
+	require.Equal(t, "test.Hidden", all[0].FullName)
+	require.Equal(t, "test.ThingService.Purge", all[7].FullName)
+
+	require.Empty(t, new(Template).AllElements(true))
+}
@@ -0,0 +1,33 @@
+package gendoc
+
+// JSONFence describes how JSON blocks are delimited in comments, so they can be pretty-printed (see
+// IndentJsonInComment). Indent is put in front of every line of the formatted JSON, for formats where code blocks are
+// delimited by indentation.
+//
+// For example, reStructuredText code blocks can be handled with:
+//
+//	JSONFence{Begin: ".. code-block:: json\n", End: "\n\n", Indent: "   "}
+type JSONFence struct {
+	Begin  string
+	End    string
+	Indent string
+}
+
+// MarkdownJSONFence is the default fence, i.e. markdown code blocks tagged with json.
+var MarkdownJSONFence = JSONFence{Begin: "```json", End: "```"}
+
+var jsonFences = []JSONFence{MarkdownJSONFence}
+
+// SetJSONFences sets the fences of the JSON blocks that are pretty-printed in descriptions. Calling it without any
+// fences disables the formatting altogether. It must be called before the Template is created.
+func SetJSONFences(fences ...JSONFence) {
+	jsonFences = fences
+}
+
+func indentJSONFences(desc string) string {
+	for _, fence := range jsonFences {
+		desc = indentJSONBlocks(desc, fence.Begin, fence.End, fence.Indent)
+	}
+
+	return desc
+}
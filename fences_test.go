@@ -0,0 +1,41 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+	. "github.com/pseudomuto/protoc-gen-doc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetJSONFences(t *testing.T) {
+	defer SetJSONFences(MarkdownJSONFence)
+
+	newThing := func(comment string) *Message {
+		tmpl := newTestTemplate(&descriptor.FileDescriptorProto{
+			Name:        proto.String("fences.proto"),
+			Package:     proto.String("test"),
+			MessageType: []*descriptor.DescriptorProto{{Name: proto.String("Thing")}},
+			SourceCodeInfo: &descriptor.SourceCodeInfo{
+				Location: []*descriptor.SourceCodeInfo_Location{
+					{Path: []int32{4, 0}, LeadingComments: proto.String(comment)},
+				},
+			},
+		})
+
+		return findMessage("Thing", tmpl.Files[0])
+	}
+
+	markdown := "Example:\n```json\n{\"a\":1}\n```"
+	require.Equal(t, "Example:\n```json\n{\n  \"a\": 1\n}\n```", newThing(markdown).Description)
+
+	SetJSONFences()
+	require.Equal(t, markdown, newThing(markdown).Description)
+
+	SetJSONFences(JSONFence{Begin: ".. code-block:: json\n", End: "\n\n", Indent: "   "})
+	require.Equal(t,
+		"Example:\n.. code-block:: json\n\n   {\n     \"a\": 1\n   }\n\nMore.",
+		newThing("Example:\n.. code-block:: json\n\n   {\"a\": 1}\n\nMore.").Description,
+	)
+}
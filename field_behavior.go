@@ -0,0 +1,89 @@
+package gendoc
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/pseudomuto/protokit"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	fieldBehaviorOption                  = "google.api.field_behavior"
+	fieldBehaviorField  protowire.Number = 1052 // FieldOptions extension number of google.api.field_behavior
+)
+
+// Names of the google.api.FieldBehavior values, for when the extension isn't registered.
+var fieldBehaviorNames = map[uint64]string{
+	1: "OPTIONAL",
+	2: "REQUIRED",
+	3: "OUTPUT_ONLY",
+	4: "INPUT_ONLY",
+	5: "IMMUTABLE",
+	6: "UNORDERED_LIST",
+	7: "NON_EMPTY_DEFAULT",
+	8: "IDENTIFIER",
+}
+
+// fieldBehaviors returns the names of the google.api.field_behavior values of a field (e.g. "OUTPUT_ONLY"). The values
+// are taken from the parsed extension when it's registered, and from the unknown fields of the options otherwise.
+func fieldBehaviors(pf *protokit.FieldDescriptor) map[string]bool {
+	behaviors := make(map[string]bool)
+
+	if ext, ok := pf.OptionExtensions[fieldBehaviorOption]; ok {
+		v := reflect.Indirect(reflect.ValueOf(ext))
+		if v.Kind() == reflect.Slice {
+			for i := 0; i < v.Len(); i++ {
+				behaviors[fmt.Sprint(v.Index(i).Interface())] = true
+			}
+		}
+	}
+
+	if opts := pf.GetOptions(); opts != nil {
+		for _, value := range repeatedVarintField(opts.ProtoReflect().GetUnknown(), fieldBehaviorField) {
+			if name, ok := fieldBehaviorNames[value]; ok {
+				behaviors[name] = true
+			}
+		}
+	}
+
+	return behaviors
+}
+
+// repeatedVarintField returns all values of the repeated varint field with the given number, packed or not.
+func repeatedVarintField(b []byte, num protowire.Number) []uint64 {
+	var values []uint64
+	for len(b) > 0 {
+		n, typ, tagLen := protowire.ConsumeTag(b)
+		if tagLen < 0 {
+			break
+		}
+		b = b[tagLen:]
+
+		valLen := protowire.ConsumeFieldValue(n, typ, b)
+		if valLen < 0 {
+			break
+		}
+
+		if n == num {
+			switch typ {
+			case protowire.VarintType:
+				v, _ := protowire.ConsumeVarint(b)
+				values = append(values, v)
+			case protowire.BytesType:
+				packed, _ := protowire.ConsumeBytes(b[:valLen])
+				for len(packed) > 0 {
+					v, vLen := protowire.ConsumeVarint(packed)
+					if vLen < 0 {
+						break
+					}
+					values = append(values, v)
+					packed = packed[vLen:]
+				}
+			}
+		}
+		b = b[valLen:]
+	}
+
+	return values
+}
@@ -0,0 +1,63 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+	. "github.com/pseudomuto/protoc-gen-doc"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestFieldReadOnlyWriteOnly(t *testing.T) {
+	// (google.api.field_behavior) = OUTPUT_ONLY, packed
+	outputOnly := newTestField("create_time", 2, descriptor.FieldDescriptorProto_TYPE_STRING, "")
+	outputOnly.Options = new(descriptor.FieldOptions)
+	packed := protowire.AppendTag(nil, 1052, protowire.BytesType)
+	packed = protowire.AppendBytes(packed, protowire.AppendVarint(nil, 3))
+	proto.MessageReflect(outputOnly.Options).SetUnknown(packed)
+
+	// (google.api.field_behavior) = INPUT_ONLY, not packed
+	inputOnly := newTestField("password", 3, descriptor.FieldDescriptorProto_TYPE_STRING, "")
+	inputOnly.Options = new(descriptor.FieldOptions)
+	unpacked := protowire.AppendTag(nil, 1052, protowire.VarintType)
+	proto.MessageReflect(inputOnly.Options).SetUnknown(protowire.AppendVarint(unpacked, 4))
+
+	tmpl := newTestTemplate(&descriptor.FileDescriptorProto{
+		Name:    proto.String("behavior.proto"),
+		Package: proto.String("test"),
+		MessageType: []*descriptor.DescriptorProto{{
+			Name: proto.String("User"),
+			Field: []*descriptor.FieldDescriptorProto{
+				newTestField("name", 1, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+				outputOnly,
+				inputOnly,
+				newTestField("id", 4, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+				newTestField("token", 5, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+			},
+		}},
+		SourceCodeInfo: &descriptor.SourceCodeInfo{
+			Location: []*descriptor.SourceCodeInfo_Location{
+				{Path: []int32{4, 0, 2, 3}, LeadingComments: proto.String(" The ID. @readonly\n")},
+				{Path: []int32{4, 0, 2, 4}, LeadingComments: proto.String(" @writeonly\n")},
+			},
+		},
+	})
+
+	msg := findMessage("User", tmpl.Files[0])
+	require.False(t, findField("name", msg).ReadOnly)
+	require.False(t, findField("name", msg).WriteOnly)
+	require.True(t, findField("create_time", msg).ReadOnly)
+	require.False(t, findField("create_time", msg).WriteOnly)
+	require.True(t, findField("password", msg).WriteOnly)
+	require.True(t, findField("id", msg).ReadOnly)
+	require.Equal(t, "The ID. ", findField("id", msg).Description)
+	require.True(t, findField("token", msg).WriteOnly)
+	require.Empty(t, findField("token", msg).Description)
+
+	directive := &Directive{Descrition: "Mirrors @readonly_fields and @writeonlyish."}
+	require.False(t, directive.ReadOnly())
+	require.False(t, directive.WriteOnly())
+	require.Equal(t, "Mirrors @readonly_fields and @writeonlyish.", directive.Descrition)
+}
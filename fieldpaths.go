@@ -0,0 +1,63 @@
+package gendoc
+
+import (
+	"strings"
+)
+
+// defaultFieldPathDepth is used when TemplateOptions.MaxFieldPathDepth isn't set.
+const defaultFieldPathDepth = 3
+
+// FieldPaths returns the dotted paths of the fields of the message, including the fields of nested (singular) message
+// fields, e.g. "user", "user.address", and "user.address.city". These are the paths that can be used in a
+// google.protobuf.FieldMask for this message.
+//
+// Nested messages are followed up to TemplateOptions.MaxFieldPathDepth levels. Repeated and map fields aren't followed,
+// since field masks can't select into them, nor are messages that already occur on the path (recursive types).
+func (m Message) FieldPaths() []string {
+	if m.fieldPaths != nil {
+		return m.fieldPaths
+	}
+
+	paths := make([]string, 0, len(m.Fields))
+	for _, field := range m.Fields {
+		paths = append(paths, field.Name)
+	}
+	return paths
+}
+
+func resolveFieldPaths(t *Template, idx *typeIndex, maxDepth int) {
+	if maxDepth < 1 {
+		maxDepth = defaultFieldPathDepth
+	}
+
+	for _, f := range t.Files {
+		for _, m := range f.Messages {
+			m.fieldPaths = fieldPaths(m, idx, nil, map[string]bool{m.FullName: true}, maxDepth)
+		}
+	}
+}
+
+// fieldPaths returns the paths of the fields of msg, prefixed with prefix. Messages in seen are on the current path and
+// won't be followed again.
+func fieldPaths(msg *Message, idx *typeIndex, prefix []string, seen map[string]bool, maxDepth int) []string {
+	paths := make([]string, 0, len(msg.Fields))
+	for _, field := range msg.Fields {
+		path := append(append([]string(nil), prefix...), field.Name)
+		paths = append(paths, strings.Join(path, "."))
+
+		if field.TypeKind != typeKindMessage || field.Label == "repeated" || len(path) >= maxDepth {
+			continue
+		}
+
+		child, ok := idx.messages[field.FullType]
+		if !ok || seen[child.FullName] {
+			continue
+		}
+
+		seen[child.FullName] = true
+		paths = append(paths, fieldPaths(child, idx, path, seen, maxDepth)...)
+		delete(seen, child.FullName)
+	}
+
+	return paths
+}
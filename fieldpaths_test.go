@@ -0,0 +1,86 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+	. "github.com/pseudomuto/protoc-gen-doc"
+	"github.com/stretchr/testify/require"
+)
+
+func fieldPathsFile() *descriptor.FileDescriptorProto {
+	friends := newTestField("friends", 3, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".test.User")
+	friends.Label = descriptor.FieldDescriptorProto_LABEL_REPEATED.Enum()
+
+	return &descriptor.FileDescriptorProto{
+		Name:    proto.String("paths.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptor.DescriptorProto{
+			{
+				Name: proto.String("UpdateUserRequest"),
+				Field: []*descriptor.FieldDescriptorProto{
+					newTestField("user", 1, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".test.User"),
+				},
+			},
+			{
+				Name: proto.String("User"),
+				Field: []*descriptor.FieldDescriptorProto{
+					newTestField("name", 1, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+					newTestField("address", 2, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".test.Address"),
+					friends,
+					newTestField("manager", 4, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".test.User"),
+				},
+			},
+			{
+				Name: proto.String("Address"),
+				Field: []*descriptor.FieldDescriptorProto{
+					newTestField("city", 1, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+					newTestField("geo", 2, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".test.Geo"),
+				},
+			},
+			{
+				Name: proto.String("Geo"),
+				Field: []*descriptor.FieldDescriptorProto{
+					newTestField("lat", 1, descriptor.FieldDescriptorProto_TYPE_DOUBLE, ""),
+				},
+			},
+		},
+	}
+}
+
+func TestFieldPaths(t *testing.T) {
+	file := newTestTemplate(fieldPathsFile()).Files[0]
+
+	require.Equal(t, []string{
+		"user",
+		"user.name",
+		"user.address",
+		"user.address.city",
+		"user.address.geo",
+		"user.friends",
+		"user.manager",
+	}, findMessage("UpdateUserRequest", file).FieldPaths())
+
+	require.Equal(t, []string{
+		"name",
+		"address",
+		"address.city",
+		"address.geo",
+		"address.geo.lat",
+		"friends",
+		"manager",
+	}, findMessage("User", file).FieldPaths())
+}
+
+func TestFieldPathsWithMaxDepth(t *testing.T) {
+	tmpl := newTestTemplateWithOptions(TemplateOptions{MaxFieldPathDepth: 1}, fieldPathsFile())
+
+	require.Equal(t, []string{"user"}, findMessage("UpdateUserRequest", tmpl.Files[0]).FieldPaths())
+}
+
+func TestFieldPathsWithoutTemplate(t *testing.T) {
+	msg := Message{Fields: []*MessageField{{Name: "id"}, {Name: "name"}}}
+	require.Equal(t, []string{"id", "name"}, msg.FieldPaths())
+}
@@ -0,0 +1,78 @@
+package gendoc
+
+import (
+	"strconv"
+)
+
+// Columns of a field table (see Message.FieldTableColumns).
+const (
+	FieldColumnName        = "name"
+	FieldColumnNumber      = "number"
+	FieldColumnType        = "type"
+	FieldColumnLabel       = "label"
+	FieldColumnDescription = "description"
+)
+
+// FieldTable returns a row of [name, type, label, description] cells per field, which is what's needed for the usual
+// fields table. The header row is left to the template.
+func (m Message) FieldTable() [][]string {
+	return m.FieldTableColumns(FieldColumnName, FieldColumnType, FieldColumnLabel, FieldColumnDescription)
+}
+
+// FieldTableWithNumbers is like FieldTable, but with the field number as the first column.
+func (m Message) FieldTableWithNumbers() [][]string {
+	return m.FieldTableColumns(FieldColumnNumber, FieldColumnName, FieldColumnType, FieldColumnLabel, FieldColumnDescription)
+}
+
+// FieldTableColumns returns a row per field with the given columns (see the FieldColumn constants). Unknown columns
+// result in empty cells.
+//
+// Map fields have a type like "map<string, Vehicle>". Fields that are part of a oneof are labeled "oneof <name>", and
+// fields marked with the `@required` directive are labeled "required".
+func (m Message) FieldTableColumns(columns ...string) [][]string {
+	rows := make([][]string, 0, len(m.Fields))
+	for _, field := range m.Fields {
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			switch column {
+			case FieldColumnName:
+				row[i] = field.Name
+			case FieldColumnNumber:
+				row[i] = strconv.Itoa(field.Number)
+			case FieldColumnType:
+				row[i] = fieldTableType(field)
+			case FieldColumnLabel:
+				row[i] = fieldTableLabel(field)
+			case FieldColumnDescription:
+				row[i] = field.Description
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+func fieldTableType(field *MessageField) string {
+	if field.IsMap {
+		return field.MapTypeString()
+	}
+	if field.DisplayType != "" {
+		return field.DisplayType
+	}
+
+	return field.LongType
+}
+
+func fieldTableLabel(field *MessageField) string {
+	switch {
+	case field.IsOneof:
+		return "oneof " + field.OneofDecl
+	case field.Required:
+		return "required"
+	case field.LabelDisplay != "":
+		return field.LabelDisplay
+	}
+
+	return field.Label
+}
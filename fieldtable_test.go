@@ -0,0 +1,25 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	. "github.com/pseudomuto/protoc-gen-doc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageFieldTable(t *testing.T) {
+	rows := findMessage("Vehicle", vehicleFile).FieldTable()
+	require.Equal(t, []string{"id", "int32", "", "Unique vehicle ID."}, rows[0])
+	require.Equal(t, []string{"rates", "sint32", "repeated", "rates"}, rows[6])
+	require.Equal(t, []string{"properties", "map<string, string>", "repeated", "bag of properties related to the vehicle."}, rows[7])
+	require.Equal(t, []string{"kilometers", "int32", "oneof travel", ""}, rows[8])
+
+	rows = findMessage("Vehicle", vehicleFile).FieldTableWithNumbers()
+	require.Equal(t, []string{"1", "id", "int32", "", "Unique vehicle ID."}, rows[0])
+
+	msg := Message{Fields: []*MessageField{{Name: "id", Label: "optional", Required: true, LongType: "string", DisplayType: "uuid"}}}
+	require.Equal(t, [][]string{{"uuid", "required", ""}}, msg.FieldTableColumns(FieldColumnType, FieldColumnLabel, "unknown"))
+
+	msg = Message{Fields: []*MessageField{{Name: "labels", IsMap: true, LongType: "LabelsEntry"}}}
+	require.Equal(t, [][]string{{"LabelsEntry"}}, msg.FieldTableColumns(FieldColumnType))
+}
@@ -0,0 +1,69 @@
+package gendoc
+
+import (
+	"sort"
+	"strings"
+)
+
+// FileSummary is an entry of the file index (see Template.FileIndex).
+//
+// Summary is the first paragraph of the file's description, on a single line.
+type FileSummary struct {
+	Name           string `json:"name"`
+	Package        string `json:"package"`
+	Category       string `json:"category"`
+	Anchor         string `json:"anchor"`
+	Summary        string `json:"summary"`
+	MessageCount   int    `json:"messageCount"`
+	EnumCount      int    `json:"enumCount"`
+	ServiceCount   int    `json:"serviceCount"`
+	ExtensionCount int    `json:"extensionCount"`
+}
+
+// Anchor returns the identifier used to link to this file in the generated docs.
+func (f File) Anchor() string { return f.Name }
+
+// FileIndex returns a summary of every file, e.g. for a landing page that links to the docs of each file. The
+// summaries are sorted by category and then by name, with the files that don't have a category last (like
+// FilesByCategory). Excluded files are left out.
+func (t *Template) FileIndex() []FileSummary {
+	index := make([]FileSummary, 0, len(t.Files))
+	for _, f := range t.Files {
+		if f.Exclude {
+			continue
+		}
+
+		index = append(index, FileSummary{
+			Name:           f.Name,
+			Package:        f.Package,
+			Category:       f.Category,
+			Anchor:         f.Anchor(),
+			Summary:        summary(f.Description),
+			MessageCount:   len(f.Messages),
+			EnumCount:      len(f.Enums),
+			ServiceCount:   len(f.Services),
+			ExtensionCount: len(f.Extensions),
+		})
+	}
+
+	sort.SliceStable(index, func(i, j int) bool {
+		if (index[i].Category == "") != (index[j].Category == "") {
+			return index[j].Category == ""
+		}
+		if index[i].Category != index[j].Category {
+			return index[i].Category < index[j].Category
+		}
+		return index[i].Name < index[j].Name
+	})
+
+	return index
+}
+
+// summary returns the first paragraph of desc with its lines joined by spaces.
+func summary(desc string) string {
+	paragraph := strings.TrimSpace(desc)
+	if i := strings.Index(paragraph, "\n\n"); i >= 0 {
+		paragraph = paragraph[:i]
+	}
+	return strings.Join(strings.Fields(paragraph), " ")
+}
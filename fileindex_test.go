@@ -0,0 +1,53 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+	. "github.com/pseudomuto/protoc-gen-doc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileIndex(t *testing.T) {
+	file := func(name, comment string) *descriptor.FileDescriptorProto {
+		return &descriptor.FileDescriptorProto{
+			Name:        proto.String(name),
+			Package:     proto.String("test"),
+			Syntax:      proto.String("proto3"),
+			MessageType: []*descriptor.DescriptorProto{{Name: proto.String("Thing")}},
+			SourceCodeInfo: &descriptor.SourceCodeInfo{
+				Location: []*descriptor.SourceCodeInfo_Location{
+					{Path: []int32{12}, LeadingComments: proto.String(comment)},
+				},
+			},
+		}
+	}
+
+	tmpl := newTestTemplate(
+		file("zoo.proto", " Animals.\n"),
+		file("things.proto", " Things and\n stuff.\n\n More details.\n @category Core\n"),
+		file("hidden.proto", " @exclude\n"),
+		file("apps.proto", " @category Apps\n"),
+		file("base.proto", " Basics. @category Core\n"),
+	)
+
+	index := tmpl.FileIndex()
+
+	var names []string
+	for _, summary := range index {
+		names = append(names, summary.Name)
+	}
+	require.Equal(t, []string{"apps.proto", "base.proto", "things.proto", "zoo.proto"}, names)
+
+	require.Equal(t, FileSummary{
+		Name:         "things.proto",
+		Package:      "test",
+		Category:     "Core",
+		Anchor:       "things.proto",
+		Summary:      "Things and stuff.",
+		MessageCount: 1,
+	}, index[2])
+	require.Equal(t, "Animals.", index[3].Summary)
+	require.Empty(t, index[3].Category)
+}
@@ -0,0 +1,90 @@
+package gendoc
+
+import (
+	"sort"
+	"strings"
+)
+
+// ImportCycles returns the cycles in the import graph of the files in the template (see File.Dependencies). Each cycle
+// lists the names of the files involved, starting with the lowest one, such that every file imports the next and the
+// last imports the first. Imports of files that aren't part of the template are ignored.
+//
+// protoc rejects circular imports, but they can show up in merged descriptor sets. The result is empty when the graph
+// is acyclic.
+func (t *Template) ImportCycles() [][]string {
+	imports := make(map[string][]string, len(t.Files))
+	names := make([]string, 0, len(t.Files))
+	for _, f := range t.Files {
+		names = append(names, f.Name)
+		imports[f.Name] = f.Dependencies
+	}
+	sort.Strings(names)
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[string]int, len(names))
+	seen := make(map[string]bool)
+	cycles := make([][]string, 0)
+	var stack []string
+
+	var visit func(name string)
+	visit = func(name string) {
+		state[name] = visiting
+		stack = append(stack, name)
+
+		deps := append([]string(nil), imports[name]...)
+		sort.Strings(deps)
+		for _, dep := range deps {
+			if _, ok := imports[dep]; !ok {
+				continue
+			}
+
+			switch state[dep] {
+			case unvisited:
+				visit(dep)
+			case visiting:
+				cycle := rotateCycle(cycleFrom(stack, dep))
+				if key := strings.Join(cycle, "\x00"); !seen[key] {
+					seen[key] = true
+					cycles = append(cycles, cycle)
+				}
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[name] = visited
+	}
+
+	for _, name := range names {
+		if state[name] == unvisited {
+			visit(name)
+		}
+	}
+
+	return cycles
+}
+
+// cycleFrom returns a copy of the part of stack starting at name.
+func cycleFrom(stack []string, name string) []string {
+	for i := len(stack) - 1; i >= 0; i-- {
+		if stack[i] == name {
+			return append([]string(nil), stack[i:]...)
+		}
+	}
+	return nil
+}
+
+// rotateCycle rotates cycle so that it starts with its lowest name.
+func rotateCycle(cycle []string) []string {
+	start := 0
+	for i, name := range cycle {
+		if name < cycle[start] {
+			start = i
+		}
+	}
+	return append(append(make([]string, 0, len(cycle)), cycle[start:]...), cycle[:start]...)
+}
@@ -0,0 +1,49 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+	"github.com/stretchr/testify/require"
+)
+
+func importingFile(name string, deps ...string) *descriptor.FileDescriptorProto {
+	return &descriptor.FileDescriptorProto{
+		Name:       proto.String(name),
+		Package:    proto.String("test"),
+		Syntax:     proto.String("proto3"),
+		Dependency: deps,
+	}
+}
+
+func TestFileDependencies(t *testing.T) {
+	require.Equal(t, []string{"github.com/pseudomuto/protokit/fixtures/extend.proto"}, bookingFile.Dependencies)
+	require.Empty(t, cookieFile.Dependencies)
+}
+
+func TestImportCycles(t *testing.T) {
+	tmpl := newTestTemplate(
+		importingFile("c.proto", "a.proto"),
+		importingFile("a.proto", "b.proto", "google/protobuf/empty.proto"),
+		importingFile("b.proto", "c.proto"),
+		importingFile("d.proto", "d.proto"),
+		importingFile("e.proto", "a.proto"),
+	)
+
+	require.Equal(t, [][]string{
+		{"a.proto", "b.proto", "c.proto"},
+		{"d.proto"},
+	}, tmpl.ImportCycles())
+}
+
+func TestImportCyclesWhenAcyclic(t *testing.T) {
+	require.Empty(t, template.ImportCycles())
+
+	tmpl := newTestTemplate(
+		importingFile("a.proto", "b.proto", "c.proto"),
+		importingFile("b.proto", "c.proto"),
+		importingFile("c.proto"),
+	)
+	require.Empty(t, tmpl.ImportCycles())
+}
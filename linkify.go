@@ -0,0 +1,108 @@
+package gendoc
+
+import (
+	"regexp"
+	"strings"
+)
+
+var typeNameRegex = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*`)
+
+// DescSegment is a piece of a description. Segments with an Anchor mention a known message or enum and can be rendered
+// as a link to it, the others are plain text.
+type DescSegment struct {
+	Text   string `json:"text"`
+	Anchor string `json:"anchor,omitempty"`
+}
+
+// IsLink returns whether or not the segment refers to a message or enum.
+func (s DescSegment) IsLink() bool { return s.Anchor != "" }
+
+// LinkifyDescription splits a description into text and link segments, so templates can hyperlink the messages and
+// enums it mentions. Types can be mentioned by their full name, long name, or name, as long as the latter isn't
+// ambiguous. Nothing within fenced code blocks or inline code is linked.
+//
+// Concatenating the text of all segments gives back the original description.
+func (t *Template) LinkifyDescription(s string) []DescSegment {
+	targets := t.linkTargets()
+
+	var segments []DescSegment
+	addText := func(text string) {
+		if text == "" {
+			return
+		}
+		if n := len(segments); n > 0 && !segments[n-1].IsLink() {
+			segments[n-1].Text += text
+			return
+		}
+		segments = append(segments, DescSegment{Text: text})
+	}
+
+	inFence := false
+	for _, line := range strings.SplitAfter(s, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			addText(line)
+			continue
+		}
+
+		if inFence {
+			addText(line)
+			continue
+		}
+
+		// odd parts are inline code
+		for i, part := range strings.Split(line, "`") {
+			if i > 0 {
+				addText("`")
+			}
+			if i%2 == 1 {
+				addText(part)
+				continue
+			}
+
+			last := 0
+			for _, loc := range typeNameRegex.FindAllStringIndex(part, -1) {
+				anchor, ok := targets[part[loc[0]:loc[1]]]
+				if !ok {
+					continue
+				}
+				addText(part[last:loc[0]])
+				segments = append(segments, DescSegment{Text: part[loc[0]:loc[1]], Anchor: anchor})
+				last = loc[1]
+			}
+			addText(part[last:])
+		}
+	}
+
+	return segments
+}
+
+// linkTargets maps the names messages and enums can be referred to by to their anchors. Names shared by more than one
+// type are left out.
+func (t *Template) linkTargets() map[string]string {
+	targets := make(map[string]string)
+	ambiguous := make(map[string]bool)
+	add := func(anchor string, names ...string) {
+		for _, name := range names {
+			if existing, ok := targets[name]; ok && existing != anchor {
+				ambiguous[name] = true
+			}
+			targets[name] = anchor
+		}
+	}
+
+	for _, f := range t.Files {
+		for _, m := range f.Messages {
+			add(m.Anchor(), m.Name, m.LongName, m.FullName)
+		}
+		for _, e := range f.Enums {
+			add(e.Anchor(), e.Name, e.LongName, e.FullName)
+		}
+	}
+
+	for name := range ambiguous {
+		delete(targets, name)
+	}
+
+	return targets
+}
@@ -0,0 +1,33 @@
+package gendoc_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/pseudomuto/protoc-gen-doc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinkifyDescription(t *testing.T) {
+	desc := "Returns a Vehicle (see com.example.Model).\nThe Category is ambiguous, Vehicle.Category isn't.\n" +
+		"Not in `Vehicle` or:\n```\nVehicle v;\n```\n"
+
+	segments := template.LinkifyDescription(desc)
+	require.Equal(t, []DescSegment{
+		{Text: "Returns a "},
+		{Text: "Vehicle", Anchor: "com.example.Vehicle"},
+		{Text: " (see "},
+		{Text: "com.example.Model", Anchor: "com.example.Model"},
+		{Text: ").\nThe Category is ambiguous, "},
+		{Text: "Vehicle.Category", Anchor: "com.example.Vehicle.Category"},
+		{Text: " isn't.\nNot in `Vehicle` or:\n```\nVehicle v;\n```\n"},
+	}, segments)
+
+	var text strings.Builder
+	for _, segment := range segments {
+		text.WriteString(segment.Text)
+	}
+	require.Equal(t, desc, text.String())
+
+	require.Nil(t, template.LinkifyDescription(""))
+}
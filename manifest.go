@@ -0,0 +1,60 @@
+package gendoc
+
+import (
+	"sort"
+)
+
+// Manifest is a machine-readable index of the files in a Template. It's deterministic (files are sorted by name), so
+// manifests can be diffed across versions of an API.
+type Manifest struct {
+	Files []*ManifestFile `json:"files"`
+}
+
+// ManifestFile lists the messages, enums, and services defined in a single file.
+type ManifestFile struct {
+	Name     string           `json:"name"`
+	Package  string           `json:"package"`
+	Syntax   string           `json:"syntax"`
+	Messages []*ManifestEntry `json:"messages"`
+	Enums    []*ManifestEntry `json:"enums"`
+	Services []*ManifestEntry `json:"services"`
+}
+
+// ManifestEntry describes a single message, enum, or service. Count is the number of fields, values, or methods
+// respectively.
+type ManifestEntry struct {
+	FullName string `json:"fullName"`
+	Count    int    `json:"count"`
+}
+
+// Manifest builds a Manifest from the parsed files.
+func (t *Template) Manifest() Manifest {
+	files := make([]*ManifestFile, 0, len(t.Files))
+
+	for _, f := range t.Files {
+		file := &ManifestFile{
+			Name:     f.Name,
+			Package:  f.Package,
+			Syntax:   f.Syntax,
+			Messages: make([]*ManifestEntry, 0, len(f.Messages)),
+			Enums:    make([]*ManifestEntry, 0, len(f.Enums)),
+			Services: make([]*ManifestEntry, 0, len(f.Services)),
+		}
+
+		for _, m := range f.Messages {
+			file.Messages = append(file.Messages, &ManifestEntry{FullName: m.FullName, Count: len(m.Fields)})
+		}
+		for _, e := range f.Enums {
+			file.Enums = append(file.Enums, &ManifestEntry{FullName: e.FullName, Count: len(e.Values)})
+		}
+		for _, s := range f.Services {
+			file.Services = append(file.Services, &ManifestEntry{FullName: s.FullName, Count: len(s.Methods)})
+		}
+
+		files = append(files, file)
+	}
+
+	sort.SliceStable(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+
+	return Manifest{Files: files}
+}
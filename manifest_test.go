@@ -0,0 +1,36 @@
+package gendoc_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/pseudomuto/protoc-gen-doc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManifest(t *testing.T) {
+	manifest := template.Manifest()
+	require.Len(t, manifest.Files, 2)
+
+	file := manifest.Files[1]
+	require.Equal(t, "Vehicle.proto", file.Name)
+	require.Equal(t, "com.example", file.Package)
+	require.Equal(t, "proto3", file.Syntax)
+	require.Len(t, file.Messages, len(vehicleFile.Messages))
+	require.Contains(t, file.Messages, &ManifestEntry{FullName: "com.example.Model", Count: 6})
+	require.Contains(t, file.Enums, &ManifestEntry{FullName: "com.example.Type", Count: 2})
+	require.Contains(t, file.Services, &ManifestEntry{FullName: "com.example.VehicleService", Count: 3})
+
+	require.Equal(t, "proto2", manifest.Files[0].Syntax)
+}
+
+func TestManifestIsDeterministic(t *testing.T) {
+	reversed := &Template{Files: []*File{vehicleFile, bookingFile}}
+
+	expected, err := json.Marshal(template.Manifest())
+	require.NoError(t, err)
+
+	actual, err := json.Marshal(reversed.Manifest())
+	require.NoError(t, err)
+	require.JSONEq(t, string(expected), string(actual))
+}
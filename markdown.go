@@ -0,0 +1,49 @@
+package gendoc
+
+import (
+	"html/template"
+)
+
+// MarkdownRenderer converts the (markdown) description of an element into HTML.
+type MarkdownRenderer func(markdown string) string
+
+var markdownRenderer MarkdownRenderer
+
+// SetMarkdownRenderer sets the function used by the DescriptionHTML methods to convert descriptions into HTML. The
+// renderer is responsible for sanitizing its output. Passing nil restores the default behaviour, which is to HTML
+// escape the raw description.
+func SetMarkdownRenderer(f MarkdownRenderer) {
+	markdownRenderer = f
+}
+
+func descriptionHTML(desc string) template.HTML {
+	if markdownRenderer == nil {
+		return template.HTML(template.HTMLEscapeString(desc))
+	}
+
+	return template.HTML(markdownRenderer(desc))
+}
+
+// DescriptionHTML returns the description rendered with the registered MarkdownRenderer.
+func (f File) DescriptionHTML() template.HTML { return descriptionHTML(f.Description) }
+
+// DescriptionHTML returns the description rendered with the registered MarkdownRenderer.
+func (e FileExtension) DescriptionHTML() template.HTML { return descriptionHTML(e.Description) }
+
+// DescriptionHTML returns the description rendered with the registered MarkdownRenderer.
+func (m Message) DescriptionHTML() template.HTML { return descriptionHTML(m.Description) }
+
+// DescriptionHTML returns the description rendered with the registered MarkdownRenderer.
+func (f MessageField) DescriptionHTML() template.HTML { return descriptionHTML(f.Description) }
+
+// DescriptionHTML returns the description rendered with the registered MarkdownRenderer.
+func (e Enum) DescriptionHTML() template.HTML { return descriptionHTML(e.Description) }
+
+// DescriptionHTML returns the description rendered with the registered MarkdownRenderer.
+func (v EnumValue) DescriptionHTML() template.HTML { return descriptionHTML(v.Description) }
+
+// DescriptionHTML returns the description rendered with the registered MarkdownRenderer.
+func (s Service) DescriptionHTML() template.HTML { return descriptionHTML(s.Description) }
+
+// DescriptionHTML returns the description rendered with the registered MarkdownRenderer.
+func (m ServiceMethod) DescriptionHTML() template.HTML { return descriptionHTML(m.Description) }
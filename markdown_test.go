@@ -0,0 +1,29 @@
+package gendoc_test
+
+import (
+	html "html/template"
+	"strings"
+	"testing"
+
+	. "github.com/pseudomuto/protoc-gen-doc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescriptionHTMLWithoutRenderer(t *testing.T) {
+	msg := Message{Description: "Uses <b> & **bold**"}
+	require.Equal(t, html.HTML("Uses &lt;b&gt; &amp; **bold**"), msg.DescriptionHTML())
+}
+
+func TestDescriptionHTMLWithRenderer(t *testing.T) {
+	SetMarkdownRenderer(func(md string) string {
+		return "<p>" + strings.Replace(md, "**bold**", "<strong>bold</strong>", -1) + "</p>"
+	})
+	defer SetMarkdownRenderer(nil)
+
+	field := MessageField{Description: "Some **bold** text"}
+	require.Equal(t, html.HTML("<p>Some <strong>bold</strong> text</p>"), field.DescriptionHTML())
+	require.Equal(t, "Some **bold** text", field.Description)
+
+	method := ServiceMethod{Description: "**bold**"}
+	require.Equal(t, html.HTML("<p><strong>bold</strong></p>"), method.DescriptionHTML())
+}
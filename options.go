@@ -0,0 +1,353 @@
+package gendoc
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// OptionKV is a single option with its value converted to a string, see OptionsList.
+type OptionKV struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// SortedOption is a single option with its value as set in the descriptors.
+//
+// Every element keeps its options in two forms: Options, a map for looking up individual options (see Option), and
+// SortedOptions, the same options as a list sorted by key, with maps and lists of scalars in their values ordered too
+// (see canonicalOptionValue). Only the latter is included in the JSON output, so that it's byte-stable regardless of
+// the option values. Structured values (e.g. HTTP rules) are encoded as JSON objects, not strings.
+type SortedOption struct {
+	Key   string      `json:"key"`
+	Value interface{} `json:"value"`
+}
+
+// sortOptions converts a set of options into a list sorted by key.
+func sortOptions(opts map[string]interface{}) []SortedOption {
+	if len(opts) == 0 {
+		return nil
+	}
+
+	list := make([]SortedOption, 0, len(opts))
+	for key, value := range opts {
+		list = append(list, SortedOption{Key: key, Value: canonicalOptionValue(reflect.ValueOf(value))})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Key < list[j].Key })
+
+	return list
+}
+
+// excludeOptions removes the named options from all elements in the template.
+func excludeOptions(t *Template, names []string) {
+	if len(names) == 0 {
+		return
+	}
+
+	remove := func(opts map[string]interface{}) {
+		for _, name := range names {
+			delete(opts, name)
+		}
+	}
+
+	for _, f := range t.Files {
+		remove(f.Options)
+		for _, m := range f.Messages {
+			remove(m.Options)
+			for _, field := range m.Fields {
+				remove(field.Options)
+			}
+		}
+		for _, e := range f.Enums {
+			remove(e.Options)
+			for _, v := range e.Values {
+				remove(v.Options)
+			}
+		}
+		for _, s := range f.Services {
+			remove(s.Options)
+			for _, m := range s.Methods {
+				remove(m.Options)
+			}
+		}
+	}
+}
+
+// storeSortedOptions sets SortedOptions of every element in the template from its Options.
+func storeSortedOptions(t *Template) {
+	for _, f := range t.Files {
+		f.SortedOptions = sortOptions(f.Options)
+		for _, m := range f.Messages {
+			m.SortedOptions = sortOptions(m.Options)
+			for _, field := range m.Fields {
+				field.SortedOptions = sortOptions(field.Options)
+			}
+		}
+		for _, e := range f.Enums {
+			e.SortedOptions = sortOptions(e.Options)
+			for _, v := range e.Values {
+				v.SortedOptions = sortOptions(v.Options)
+			}
+		}
+		for _, s := range f.Services {
+			s.SortedOptions = sortOptions(s.Options)
+			for _, m := range s.Methods {
+				m.SortedOptions = sortOptions(m.Options)
+			}
+		}
+	}
+}
+
+// optionsList formats the stored list of options for templates. When there isn't one, it's built from the map (e.g.
+// for elements that weren't created by NewTemplate).
+func optionsList(sorted []SortedOption, opts map[string]interface{}) []OptionKV {
+	if sorted == nil {
+		sorted = sortOptions(opts)
+	}
+	if len(sorted) == 0 {
+		return nil
+	}
+
+	list := make([]OptionKV, len(sorted))
+	for i, opt := range sorted {
+		list[i] = OptionKV{Key: opt.Key, Value: optionValueString(opt.Value)}
+	}
+
+	return list
+}
+
+// OptionNames returns the names of all options set on any file, message, field, enum, enum value, service, or method in
+// the template, sorted and without duplicates. Options removed with TemplateOptions.ExcludeOptions aren't included.
+func (t *Template) OptionNames() []string {
+	seen := make(map[string]bool)
+	add := func(opts map[string]interface{}) {
+		for key := range opts {
+			seen[key] = true
+		}
+	}
+
+	for _, f := range t.Files {
+		add(f.Options)
+		for _, m := range f.Messages {
+			add(m.Options)
+			for _, field := range m.Fields {
+				add(field.Options)
+			}
+		}
+		for _, e := range f.Enums {
+			add(e.Options)
+			for _, v := range e.Values {
+				add(v.Options)
+			}
+		}
+		for _, s := range f.Services {
+			add(s.Options)
+			for _, m := range s.Methods {
+				add(m.Options)
+			}
+		}
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// optionValueString formats an option value. Pointers are dereferenced, bools and numbers are formatted with strconv,
+// and anything that isn't a scalar (e.g. the rules set by the validation extensions) is encoded as JSON. Maps and
+// lists of scalars are ordered first (see canonicalOptionValue), so the result doesn't depend on how they were built.
+func optionValueString(value interface{}) string {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Invalid:
+		return ""
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64)
+	case reflect.String:
+		return v.String()
+	}
+
+	data, err := json.Marshal(canonicalOptionValue(v))
+	if err != nil {
+		return fmt.Sprint(v.Interface())
+	}
+
+	return string(data)
+}
+
+// canonicalOptionValue returns the value with a deterministic order. Map keys are formatted as strings (which the JSON
+// encoding sorts), and lists of scalars are sorted, since such lists (e.g. the allowed values of a validation rule)
+// are sets as far as the docs are concerned. Lists of anything else keep their order, as do the fields of structs.
+func canonicalOptionValue(v reflect.Value) interface{} {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Map:
+		out := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out[optionValueString(iter.Key().Interface())] = canonicalOptionValue(iter.Value())
+		}
+		return out
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 || (v.Kind() == reflect.Slice && v.IsNil()) {
+			return v.Interface() // bytes or null
+		}
+
+		elems := make([]reflect.Value, v.Len())
+		for i := range elems {
+			elems[i] = v.Index(i)
+		}
+		if isScalarKind(v.Type().Elem().Kind()) {
+			sort.SliceStable(elems, func(i, j int) bool { return scalarLess(elems[i], elems[j]) })
+		}
+
+		out := make([]interface{}, len(elems))
+		for i, elem := range elems {
+			out[i] = canonicalOptionValue(elem)
+		}
+		return out
+	}
+
+	return v.Interface()
+}
+
+func isScalarKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// scalarLess orders two scalars of the same kind, numbers by value.
+func scalarLess(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Bool:
+		return !a.Bool() && b.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() < b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return a.Uint() < b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() < b.Float()
+	}
+	return a.String() < b.String()
+}
+
+// OptionsList returns the options of this file sorted by key, with their values formatted as strings.
+func (f File) OptionsList() []OptionKV { return optionsList(f.SortedOptions, f.Options) }
+
+// OptionsList returns the options of this message sorted by key, with their values formatted as strings.
+func (m Message) OptionsList() []OptionKV { return optionsList(m.SortedOptions, m.Options) }
+
+// OptionsList returns the options of this field sorted by key, with their values formatted as strings.
+func (f MessageField) OptionsList() []OptionKV { return optionsList(f.SortedOptions, f.Options) }
+
+// OptionsList returns the options of this enum sorted by key, with their values formatted as strings.
+func (e Enum) OptionsList() []OptionKV { return optionsList(e.SortedOptions, e.Options) }
+
+// OptionsList returns the options of this enum value sorted by key, with their values formatted as strings.
+func (v EnumValue) OptionsList() []OptionKV { return optionsList(v.SortedOptions, v.Options) }
+
+// OptionsList returns the options of this service sorted by key, with their values formatted as strings.
+func (s Service) OptionsList() []OptionKV { return optionsList(s.SortedOptions, s.Options) }
+
+// OptionsList returns the options of this method sorted by key, with their values formatted as strings.
+func (m ServiceMethod) OptionsList() []OptionKV { return optionsList(m.SortedOptions, m.Options) }
+
+// stringOption returns the named option if it's a string (or a pointer to one).
+func stringOption(opts map[string]interface{}, name string) (string, bool) {
+	switch value := opts[name].(type) {
+	case string:
+		return value, true
+	case *string:
+		if value != nil {
+			return *value, true
+		}
+	}
+	return "", false
+}
+
+// boolOption returns the named option if it's a bool (or a pointer to one).
+func boolOption(opts map[string]interface{}, name string) (bool, bool) {
+	switch value := opts[name].(type) {
+	case bool:
+		return value, true
+	case *bool:
+		if value != nil {
+			return *value, true
+		}
+	}
+	return false, false
+}
+
+// StringOption returns the value of the named option, and whether or not it's set to a string.
+func (f File) StringOption(name string) (string, bool) { return stringOption(f.Options, name) }
+
+// BoolOption returns the value of the named option, and whether or not it's set to a bool.
+func (f File) BoolOption(name string) (bool, bool) { return boolOption(f.Options, name) }
+
+// StringOption returns the value of the named option, and whether or not it's set to a string.
+func (m Message) StringOption(name string) (string, bool) { return stringOption(m.Options, name) }
+
+// BoolOption returns the value of the named option, and whether or not it's set to a bool.
+func (m Message) BoolOption(name string) (bool, bool) { return boolOption(m.Options, name) }
+
+// StringOption returns the value of the named option, and whether or not it's set to a string.
+func (f MessageField) StringOption(name string) (string, bool) { return stringOption(f.Options, name) }
+
+// BoolOption returns the value of the named option, and whether or not it's set to a bool.
+func (f MessageField) BoolOption(name string) (bool, bool) { return boolOption(f.Options, name) }
+
+// StringOption returns the value of the named option, and whether or not it's set to a string.
+func (e Enum) StringOption(name string) (string, bool) { return stringOption(e.Options, name) }
+
+// BoolOption returns the value of the named option, and whether or not it's set to a bool.
+func (e Enum) BoolOption(name string) (bool, bool) { return boolOption(e.Options, name) }
+
+// StringOption returns the value of the named option, and whether or not it's set to a string.
+func (v EnumValue) StringOption(name string) (string, bool) { return stringOption(v.Options, name) }
+
+// BoolOption returns the value of the named option, and whether or not it's set to a bool.
+func (v EnumValue) BoolOption(name string) (bool, bool) { return boolOption(v.Options, name) }
+
+// StringOption returns the value of the named option, and whether or not it's set to a string.
+func (s Service) StringOption(name string) (string, bool) { return stringOption(s.Options, name) }
+
+// BoolOption returns the value of the named option, and whether or not it's set to a bool.
+func (s Service) BoolOption(name string) (bool, bool) { return boolOption(s.Options, name) }
+
+// StringOption returns the value of the named option, and whether or not it's set to a string.
+func (m ServiceMethod) StringOption(name string) (string, bool) { return stringOption(m.Options, name) }
+
+// BoolOption returns the value of the named option, and whether or not it's set to a bool.
+func (m ServiceMethod) BoolOption(name string) (bool, bool) { return boolOption(m.Options, name) }
@@ -0,0 +1,276 @@
+package gendoc_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+	. "github.com/pseudomuto/protoc-gen-doc"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/api/annotations"
+)
+
+type testRule struct {
+	Method  string `json:"method"`
+	Pattern string `json:"pattern"`
+}
+
+func TestOptionsList(t *testing.T) {
+	enabled := true
+	field := MessageField{Options: map[string]interface{}{
+		"deprecated":         true,
+		"custom.enabled":     &enabled,
+		"custom.limit":       int32(10),
+		"custom.ratio":       0.25,
+		"custom.name":        "value",
+		"google.api.http":    &testRule{Method: "GET", Pattern: "/v1/things"},
+		"custom.missing_ptr": (*bool)(nil),
+	}}
+
+	require.Equal(t, []OptionKV{
+		{Key: "custom.enabled", Value: "true"},
+		{Key: "custom.limit", Value: "10"},
+		{Key: "custom.missing_ptr", Value: ""},
+		{Key: "custom.name", Value: "value"},
+		{Key: "custom.ratio", Value: "0.25"},
+		{Key: "deprecated", Value: "true"},
+		{Key: "google.api.http", Value: `{"method":"GET","pattern":"/v1/things"}`},
+	}, field.OptionsList())
+
+	require.Nil(t, Message{}.OptionsList())
+	require.Equal(t, []OptionKV{{Key: "deprecated", Value: "true"}}, Service{Options: map[string]interface{}{"deprecated": true}}.OptionsList())
+}
+
+func TestMultiValuedOptions(t *testing.T) {
+	limit := int32(5)
+	field := MessageField{Options: map[string]interface{}{
+		"custom.in":      []int32{10, 9, 100},
+		"custom.names":   []string{"b", "c", "a"},
+		"custom.limits":  map[int32]*int32{2: &limit, 1: &limit},
+		"custom.rules":   []*testRule{{Method: "POST", Pattern: "/b"}, {Method: "GET", Pattern: "/a"}},
+		"custom.by_name": map[string][]string{"z": {"2", "1"}, "a": nil},
+	}}
+
+	require.Equal(t, []OptionKV{
+		{Key: "custom.by_name", Value: `{"a":null,"z":["1","2"]}`},
+		{Key: "custom.in", Value: "[9,10,100]"},
+		{Key: "custom.limits", Value: `{"1":5,"2":5}`},
+		{Key: "custom.names", Value: `["a","b","c"]`},
+		{Key: "custom.rules", Value: `[{"method":"POST","pattern":"/b"},{"method":"GET","pattern":"/a"}]`},
+	}, field.OptionsList())
+}
+
+func TestTypedOptions(t *testing.T) {
+	label := "Active"
+	enabled := true
+	value := EnumValue{Options: map[string]interface{}{
+		"custom.label":       &label,
+		"custom.short":       "act",
+		"custom.enabled":     &enabled,
+		"deprecated":         false,
+		"custom.limit":       int32(10),
+		"custom.missing_ptr": (*string)(nil),
+	}}
+
+	str, ok := value.StringOption("custom.label")
+	require.True(t, ok)
+	require.Equal(t, "Active", str)
+
+	str, ok = value.StringOption("custom.short")
+	require.True(t, ok)
+	require.Equal(t, "act", str)
+
+	for _, name := range []string{"custom.limit", "custom.enabled", "custom.missing_ptr", "custom.unknown"} {
+		_, ok = value.StringOption(name)
+		require.False(t, ok, name)
+	}
+
+	b, ok := value.BoolOption("custom.enabled")
+	require.True(t, ok)
+	require.True(t, b)
+
+	b, ok = value.BoolOption("deprecated")
+	require.True(t, ok)
+	require.False(t, b)
+
+	_, ok = value.BoolOption("custom.label")
+	require.False(t, ok)
+
+	deprecated, ok := ServiceMethod{Options: map[string]interface{}{"deprecated": true}}.BoolOption("deprecated")
+	require.True(t, ok)
+	require.True(t, deprecated)
+
+	_, ok = Message{}.StringOption("custom.label")
+	require.False(t, ok)
+}
+
+func TestSortedOptions(t *testing.T) {
+	deprecated := proto.Bool(true)
+	field := newTestField("id", 1, descriptor.FieldDescriptorProto_TYPE_STRING, "")
+	field.Options = &descriptor.FieldOptions{Deprecated: deprecated}
+
+	tmpl := newTestTemplate(&descriptor.FileDescriptorProto{
+		Name:    proto.String("options.proto"),
+		Package: proto.String("test"),
+		Options: &descriptor.FileOptions{Deprecated: deprecated},
+		MessageType: []*descriptor.DescriptorProto{{
+			Name:    proto.String("Thing"),
+			Field:   []*descriptor.FieldDescriptorProto{field},
+			Options: &descriptor.MessageOptions{Deprecated: deprecated},
+		}},
+		EnumType: []*descriptor.EnumDescriptorProto{{
+			Name: proto.String("Kind"),
+			Value: []*descriptor.EnumValueDescriptorProto{{
+				Name:    proto.String("KIND_UNSPECIFIED"),
+				Number:  proto.Int32(0),
+				Options: &descriptor.EnumValueOptions{Deprecated: deprecated},
+			}},
+			Options: &descriptor.EnumOptions{Deprecated: deprecated},
+		}},
+		Service: []*descriptor.ServiceDescriptorProto{{
+			Name: proto.String("ThingService"),
+			Method: []*descriptor.MethodDescriptorProto{{
+				Name:       proto.String("GetThing"),
+				InputType:  proto.String(".test.Thing"),
+				OutputType: proto.String(".test.Thing"),
+				Options: &descriptor.MethodOptions{
+					Deprecated:       deprecated,
+					IdempotencyLevel: descriptor.MethodOptions_NO_SIDE_EFFECTS.Enum(),
+				},
+			}},
+			Options: &descriptor.ServiceOptions{Deprecated: deprecated},
+		}},
+	})
+
+	file := tmpl.Files[0]
+	msg := findMessage("Thing", file)
+	enum := findEnum("Kind", file)
+	service := findService("ThingService", file)
+
+	elements := []struct {
+		sorted  []SortedOption
+		list    []OptionKV
+		element interface{}
+	}{
+		{file.SortedOptions, file.OptionsList(), file},
+		{msg.SortedOptions, msg.OptionsList(), msg},
+		{msg.Fields[0].SortedOptions, msg.Fields[0].OptionsList(), msg.Fields[0]},
+		{enum.SortedOptions, enum.OptionsList(), enum},
+		{enum.Values[0].SortedOptions, enum.Values[0].OptionsList(), enum.Values[0]},
+		{service.SortedOptions, service.OptionsList(), service},
+		{service.Methods[0].SortedOptions, service.Methods[0].OptionsList(), service.Methods[0]},
+	}
+
+	for i, e := range elements {
+		require.Contains(t, e.sorted, SortedOption{Key: "deprecated", Value: true}, i)
+		require.Contains(t, e.list, OptionKV{Key: "deprecated", Value: "true"}, i)
+		require.Len(t, e.list, len(e.sorted), i)
+
+		data, err := json.Marshal(e.element)
+		require.NoError(t, err)
+
+		var decoded struct {
+			Options []SortedOption `json:"options"`
+		}
+		require.NoError(t, json.Unmarshal(data, &decoded), i)
+		require.Equal(t, e.sorted, decoded.Options, i)
+	}
+
+	require.Equal(t, []SortedOption{
+		{Key: "deprecated", Value: true},
+		{Key: "idempotency_level", Value: "NO_SIDE_EFFECTS"},
+	}, service.Methods[0].SortedOptions)
+}
+
+func TestSortedOptionsKeepStructuredValues(t *testing.T) {
+	opts := new(descriptor.MethodOptions)
+	require.NoError(t, proto.SetExtension(opts, annotations.E_Http, &annotations.HttpRule{
+		Pattern: &annotations.HttpRule_Get{Get: "/v1/things"},
+	}))
+
+	method := findService("ThingService", newTestTemplate(&descriptor.FileDescriptorProto{
+		Name:    proto.String("options.proto"),
+		Package: proto.String("test"),
+		Service: []*descriptor.ServiceDescriptorProto{{
+			Name: proto.String("ThingService"),
+			Method: []*descriptor.MethodDescriptorProto{{
+				Name:       proto.String("GetThing"),
+				InputType:  proto.String(".google.protobuf.Empty"),
+				OutputType: proto.String(".google.protobuf.Empty"),
+				Options:    opts,
+			}},
+		}},
+	}).Files[0]).Methods[0]
+
+	data, err := json.Marshal(method)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `{"key":"google.api.http","value":{"rules":[{"method":"GET","pattern":"/v1/things"}]}}`)
+
+	require.Equal(t, []OptionKV{
+		{Key: "google.api.http", Value: `{"rules":[{"method":"GET","pattern":"/v1/things"}]}`},
+	}, method.OptionsList())
+}
+
+func TestExcludeOptions(t *testing.T) {
+	fd := &descriptor.FileDescriptorProto{
+		Name:    proto.String("options.proto"),
+		Package: proto.String("test"),
+		Service: []*descriptor.ServiceDescriptorProto{{
+			Name: proto.String("ThingService"),
+			Method: []*descriptor.MethodDescriptorProto{{
+				Name:       proto.String("GetThing"),
+				InputType:  proto.String(".google.protobuf.Empty"),
+				OutputType: proto.String(".google.protobuf.Empty"),
+				Options: &descriptor.MethodOptions{
+					Deprecated:       proto.Bool(true),
+					IdempotencyLevel: descriptor.MethodOptions_IDEMPOTENT.Enum(),
+				},
+			}},
+		}},
+	}
+
+	tmpl := newTestTemplateWithOptions(TemplateOptions{ExcludeOptions: []string{"deprecated"}}, fd)
+
+	method := findService("ThingService", tmpl.Files[0]).Methods[0]
+	require.Nil(t, method.Option("deprecated"))
+	require.Equal(t, []OptionKV{{Key: "idempotency_level", Value: "IDEMPOTENT"}}, method.OptionsList())
+
+	method = findService("ThingService", newTestTemplate(fd).Files[0]).Methods[0]
+	require.Equal(t, true, method.Option("deprecated"))
+}
+
+func TestOptionNames(t *testing.T) {
+	fd := &descriptor.FileDescriptorProto{
+		Name:    proto.String("options.proto"),
+		Package: proto.String("test"),
+		MessageType: []*descriptor.DescriptorProto{{
+			Name:    proto.String("Thing"),
+			Options: &descriptor.MessageOptions{Deprecated: proto.Bool(true)},
+		}},
+		EnumType: []*descriptor.EnumDescriptorProto{{
+			Name: proto.String("Kind"),
+			Value: []*descriptor.EnumValueDescriptorProto{{
+				Name:    proto.String("KIND_UNSPECIFIED"),
+				Number:  proto.Int32(0),
+				Options: &descriptor.EnumValueOptions{Deprecated: proto.Bool(true)},
+			}},
+		}},
+		Service: []*descriptor.ServiceDescriptorProto{{
+			Name: proto.String("ThingService"),
+			Method: []*descriptor.MethodDescriptorProto{{
+				Name:       proto.String("GetThing"),
+				InputType:  proto.String(".test.Thing"),
+				OutputType: proto.String(".test.Thing"),
+				Options:    &descriptor.MethodOptions{IdempotencyLevel: descriptor.MethodOptions_NO_SIDE_EFFECTS.Enum()},
+			}},
+		}},
+	}
+
+	require.Equal(t, []string{"deprecated", "idempotency_level"}, newTestTemplate(fd).OptionNames())
+
+	tmpl := newTestTemplateWithOptions(TemplateOptions{ExcludeOptions: []string{"deprecated"}}, fd)
+	require.Equal(t, []string{"idempotency_level"}, tmpl.OptionNames())
+
+	require.Empty(t, new(Template).OptionNames())
+}
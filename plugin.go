@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"path"
 	"regexp"
+	"strconv"
 	"strings"
 
 	plugin_go "github.com/golang/protobuf/protoc-gen-go/plugin"
@@ -19,6 +20,7 @@ type PluginOptions struct {
 	TemplateFile    string
 	OutputFile      string
 	ExcludePatterns []*regexp.Regexp
+	TemplateOptions TemplateOptions
 }
 
 // SupportedFeatures describes a flag setting for supported features.
@@ -36,7 +38,7 @@ func (p *Plugin) Generate(r *plugin_go.CodeGeneratorRequest) (*plugin_go.CodeGen
 	}
 
 	result := excludeUnwantedProtos(protokit.ParseCodeGenRequest(r), options.ExcludePatterns)
-	template := NewTemplate(result)
+	template := NewTemplateWithOptions(result, options.TemplateOptions)
 
 	customTemplate := ""
 
@@ -84,8 +86,10 @@ OUTER:
 // ParseOptions parses plugin options from a CodeGeneratorRequest. It does this by splitting the `Parameter` field from
 // the request object and parsing out the type of renderer to use and the name of the file to be generated.
 //
-// The parameter (`--doc_opt`) must be of the format <TYPE|TEMPLATE_FILE>,<OUTPUT_FILE>:<EXCLUDE_PATTERN>,<EXCLUDE_PATTERN>*.
-// The file will be written to the directory specified with the `--doc_out` argument to protoc.
+// The parameter (`--doc_opt`) must be of the format
+// <TYPE|TEMPLATE_FILE>,<OUTPUT_FILE>[,<KEY>=<VALUE>]*:<EXCLUDE_PATTERN>,<EXCLUDE_PATTERN>*.
+// The file will be written to the directory specified with the `--doc_out` argument to protoc. The optional key/value
+// pairs set the TemplateOptions (e.g. `inline_enum_values=true`).
 func ParseOptions(req *plugin_go.CodeGeneratorRequest) (*PluginOptions, error) {
 	options := &PluginOptions{
 		Type:         RenderTypeHTML,
@@ -94,10 +98,9 @@ func ParseOptions(req *plugin_go.CodeGeneratorRequest) (*PluginOptions, error) {
 	}
 
 	params := req.GetParameter()
-	if strings.Contains(params, ":") {
+	if i := excludePatternsIndex(params); i >= 0 {
 		// Parse out exclude patterns if any
-		parts := strings.Split(params, ":")
-		for _, pattern := range strings.Split(parts[1], ",") {
+		for _, pattern := range strings.Split(params[i+1:], ",") {
 			r, err := regexp.Compile(pattern)
 			if err != nil {
 				return nil, err
@@ -105,7 +108,7 @@ func ParseOptions(req *plugin_go.CodeGeneratorRequest) (*PluginOptions, error) {
 			options.ExcludePatterns = append(options.ExcludePatterns, r)
 		}
 		// The first part is parsed below
-		params = parts[0]
+		params = params[:i]
 	}
 	if params == "" {
 		return options, nil
@@ -116,13 +119,29 @@ func ParseOptions(req *plugin_go.CodeGeneratorRequest) (*PluginOptions, error) {
 	}
 
 	parts := strings.Split(params, ",")
-	if len(parts) != 2 {
+	if len(parts) < 2 {
 		return nil, fmt.Errorf("Invalid parameter: %s", params)
 	}
 
 	options.TemplateFile = parts[0]
 	options.OutputFile = path.Base(parts[1])
 
+	// values of list options (e.g. exclude_options=a,b) are split up like the options themselves
+	var templateParams []string
+	for _, param := range parts[2:] {
+		if n := len(templateParams); n > 0 && !strings.Contains(param, "=") {
+			templateParams[n-1] += "," + param
+			continue
+		}
+		templateParams = append(templateParams, param)
+	}
+
+	for _, param := range templateParams {
+		if err := parseTemplateOption(&options.TemplateOptions, param); err != nil {
+			return nil, err
+		}
+	}
+
 	renderType, err := NewRenderType(options.TemplateFile)
 	if err == nil {
 		options.Type = renderType
@@ -131,3 +150,97 @@ func ParseOptions(req *plugin_go.CodeGeneratorRequest) (*PluginOptions, error) {
 
 	return options, nil
 }
+
+// excludePatternsIndex returns the index of the colon that separates the exclude patterns from the rest of the parameter,
+// or -1 if there are none. Both option values (e.g. comment_prefix=DOC:) and patterns (e.g. `(?:foo)`) may contain
+// colons, so the key/value options are found first, and the separator is the first colon after the key of the last one.
+// When that value ends with a colon itself, the patterns follow a second one (e.g. comment_prefix=DOC::google/*), and a
+// colon that ends the parameter is part of the value rather than the start of an empty list of patterns.
+func excludePatternsIndex(params string) int {
+	start, offset := 0, 0
+	for i, param := range strings.Split(params, ",") {
+		if eq := strings.Index(param, "="); i >= 2 && eq > 0 && !strings.Contains(param[:eq], ":") {
+			start = offset + eq + 1
+		}
+		offset += len(param) + 1
+	}
+
+	i := strings.Index(params[start:], ":")
+	if i < 0 {
+		return -1
+	}
+	i += start
+	for i+1 < len(params) && params[i+1] == ':' {
+		i++
+	}
+	if start > 0 && i == len(params)-1 {
+		return -1
+	}
+	return i
+}
+
+func parseTemplateOption(opts *TemplateOptions, param string) error {
+	kv := strings.SplitN(param, "=", 2)
+	if len(kv) != 2 {
+		return fmt.Errorf("Invalid parameter: %s", param)
+	}
+
+	var err error
+	switch kv[0] {
+	case "inline_enum_values":
+		opts.InlineEnumValues, err = strconv.ParseBool(kv[1])
+	case "max_default_value_len":
+		opts.MaxDefaultValueLen, err = strconv.Atoi(kv[1])
+		if err == nil && opts.MaxDefaultValueLen < 0 {
+			err = fmt.Errorf("negative length")
+		}
+	case "max_inline_depth":
+		opts.MaxInlineDepth, err = strconv.Atoi(kv[1])
+		if err == nil && opts.MaxInlineDepth < 1 {
+			err = fmt.Errorf("depth must be at least 1")
+		}
+	case "max_map_value_depth":
+		opts.MaxMapValueDepth, err = strconv.Atoi(kv[1])
+		if err == nil && opts.MaxMapValueDepth < 0 {
+			err = fmt.Errorf("negative depth")
+		}
+	case "max_field_path_depth":
+		opts.MaxFieldPathDepth, err = strconv.Atoi(kv[1])
+		if err == nil && opts.MaxFieldPathDepth < 1 {
+			err = fmt.Errorf("depth must be at least 1")
+		}
+	case "used_scalars_only":
+		opts.UsedScalarsOnly, err = strconv.ParseBool(kv[1])
+	case "dynamic_json_types":
+		opts.DynamicJSONTypes, err = strconv.ParseBool(kv[1])
+	case "root_package":
+		opts.RootPackage = kv[1]
+	case "comment_prefix":
+		opts.CommentPrefix = kv[1]
+	case "default_description":
+		opts.DefaultDescription = kv[1]
+	case "normalize_whitespace":
+		opts.NormalizeWhitespace, err = strconv.ParseBool(kv[1])
+	case "admonitions":
+		opts.Admonitions, err = strconv.ParseBool(kv[1])
+	case "exclude_options":
+		opts.ExcludeOptions = strings.Split(kv[1], ",")
+	case "estimate_sizes":
+		opts.EstimateSizes, err = strconv.ParseBool(kv[1])
+	case "stability_risks":
+		opts.StabilityRisks, err = strconv.ParseBool(kv[1])
+	case "label_optional", "label_required", "label_repeated":
+		if opts.LabelNames == nil {
+			opts.LabelNames = make(map[string]string)
+		}
+		opts.LabelNames[strings.TrimPrefix(kv[0], "label_")] = kv[1]
+	default:
+		return fmt.Errorf("Unknown option: %s", kv[0])
+	}
+
+	if err != nil {
+		return fmt.Errorf("Invalid value for %s: %s", kv[0], kv[1])
+	}
+
+	return nil
+}
@@ -60,12 +60,98 @@ func TestParseOptionsForExcludePatterns(t *testing.T) {
 	require.Equal(t, pattern1.String(), options.ExcludePatterns[1].String())
 }
 
+func TestParseOptionsForTemplateOptions(t *testing.T) {
+	req := new(plugin_go.CodeGeneratorRequest)
+	req.Parameter = proto.String("markdown,output.md,inline_enum_values=true,max_default_value_len=20,estimate_sizes=1,stability_risks=true,label_repeated=list,max_inline_depth=3,max_field_path_depth=5,max_map_value_depth=2,default_description=TBD,comment_prefix=DOC:,root_package=com.example,dynamic_json_types=true,used_scalars_only=true,normalize_whitespace=true,admonitions=true,exclude_options=deprecated,internal.owner:google/*")
+
+	options, err := ParseOptions(req)
+	require.NoError(t, err)
+	require.Equal(t, RenderTypeMarkdown, options.Type)
+	require.Equal(t, "output.md", options.OutputFile)
+	require.True(t, options.TemplateOptions.InlineEnumValues)
+	require.Equal(t, 20, options.TemplateOptions.MaxDefaultValueLen)
+	require.True(t, options.TemplateOptions.EstimateSizes)
+	require.True(t, options.TemplateOptions.StabilityRisks)
+	require.Equal(t, 3, options.TemplateOptions.MaxInlineDepth)
+	require.Equal(t, 5, options.TemplateOptions.MaxFieldPathDepth)
+	require.Equal(t, 2, options.TemplateOptions.MaxMapValueDepth)
+	require.Equal(t, "TBD", options.TemplateOptions.DefaultDescription)
+	require.Equal(t, "DOC:", options.TemplateOptions.CommentPrefix)
+	require.Equal(t, "com.example", options.TemplateOptions.RootPackage)
+	require.True(t, options.TemplateOptions.DynamicJSONTypes)
+	require.True(t, options.TemplateOptions.UsedScalarsOnly)
+	require.True(t, options.TemplateOptions.NormalizeWhitespace)
+	require.True(t, options.TemplateOptions.Admonitions)
+	require.Equal(t, []string{"deprecated", "internal.owner"}, options.TemplateOptions.ExcludeOptions)
+	require.Equal(t, map[string]string{"repeated": "list"}, options.TemplateOptions.LabelNames)
+	require.Len(t, options.ExcludePatterns, 1)
+
+	req.Parameter = proto.String("markdown,output.md,comment_prefix=DOC:")
+	options, err = ParseOptions(req)
+	require.NoError(t, err)
+	require.Equal(t, "DOC:", options.TemplateOptions.CommentPrefix)
+	require.Empty(t, options.ExcludePatterns)
+
+	req.Parameter = proto.String("markdown,output.md,comment_prefix=DOC::google/*,internal/*")
+	options, err = ParseOptions(req)
+	require.NoError(t, err)
+	require.Equal(t, "DOC:", options.TemplateOptions.CommentPrefix)
+	require.Len(t, options.ExcludePatterns, 2)
+	require.Equal(t, "google/*", options.ExcludePatterns[0].String())
+	require.Equal(t, "internal/*", options.ExcludePatterns[1].String())
+
+	req.Parameter = proto.String("markdown,output.md:comment_prefix=.*,google/*")
+	options, err = ParseOptions(req)
+	require.NoError(t, err)
+	require.Empty(t, options.TemplateOptions.CommentPrefix)
+	require.Len(t, options.ExcludePatterns, 2)
+
+	req.Parameter = proto.String("markdown,output.md,comment_prefix=DOC:,inline_enum_values=true")
+	options, err = ParseOptions(req)
+	require.NoError(t, err)
+	require.Equal(t, "DOC:", options.TemplateOptions.CommentPrefix)
+	require.True(t, options.TemplateOptions.InlineEnumValues)
+	require.Empty(t, options.ExcludePatterns)
+
+	req.Parameter = proto.String("markdown,output.md,comment_prefix=DOC:,inline_enum_values=true:(?:google|internal)/.*,vendor/*")
+	options, err = ParseOptions(req)
+	require.NoError(t, err)
+	require.Equal(t, "DOC:", options.TemplateOptions.CommentPrefix)
+	require.True(t, options.TemplateOptions.InlineEnumValues)
+	require.Len(t, options.ExcludePatterns, 2)
+	require.Equal(t, "(?:google|internal)/.*", options.ExcludePatterns[0].String())
+	require.Equal(t, "vendor/*", options.ExcludePatterns[1].String())
+
+	req.Parameter = proto.String("markdown,output.md:(?:google)/.*")
+	options, err = ParseOptions(req)
+	require.NoError(t, err)
+	require.Equal(t, "output.md", options.OutputFile)
+	require.Len(t, options.ExcludePatterns, 1)
+	require.Equal(t, "(?:google)/.*", options.ExcludePatterns[0].String())
+
+	req.Parameter = proto.String("markdown,output.md")
+	options, err = ParseOptions(req)
+	require.NoError(t, err)
+	require.Equal(t, TemplateOptions{}, options.TemplateOptions)
+}
+
 func TestParseOptionsWithInvalidValues(t *testing.T) {
 	badValues := []string{
 		"markdown",
 		"html",
 		"/some/path.tmpl",
 		"more,than,1,comma",
+		"html,index.html,unknown_option=true",
+		"html,index.html,inline_enum_values=maybe",
+		"html,index.html,max_default_value_len=-1",
+		"html,index.html,estimate_sizes=yes",
+		"html,index.html,stability_risks=maybe",
+		"html,index.html,used_scalars_only=maybe",
+		"html,index.html,admonitions=maybe",
+		"html,index.html,max_inline_depth=0",
+		"html,index.html,max_field_path_depth=0",
+		"html,index.html,max_map_value_depth=-1",
+		"html,index.html,inline_enum_values=true,false",
 	}
 
 	for _, value := range badValues {
@@ -5,9 +5,11 @@ import (
 	"encoding/json"
 	"errors"
 	html_template "html/template"
+	"io"
 	text_template "text/template"
 
 	"github.com/Masterminds/sprig"
+	"github.com/pseudomuto/protokit"
 )
 
 // RenderType is an "enum" for which type of renderer to use.
@@ -107,6 +109,23 @@ func RenderTemplate(kind RenderType, template *Template, inputTemplate string) (
 	return processor.Apply(template)
 }
 
+// Render builds a Template from the descriptors and writes it to w in the given format, which is one of the built-in
+// render types ("docbook", "html", "json", or "markdown"). Unknown formats return an error without writing anything.
+func Render(descs []*protokit.FileDescriptor, format string, w io.Writer) error {
+	kind, err := NewRenderType(format)
+	if err != nil {
+		return err
+	}
+
+	data, err := RenderTemplate(kind, NewTemplate(descs), "")
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(data)
+	return err
+}
+
 type textRenderer struct {
 	inputTemplate string
 }
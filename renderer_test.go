@@ -1,6 +1,8 @@
 package gendoc_test
 
 import (
+	"bytes"
+	"encoding/json"
 	"os"
 	"testing"
 
@@ -54,3 +56,25 @@ func TestNewRenderTypeUnknown(t *testing.T) {
 	require.Zero(t, rt)
 	require.Error(t, err)
 }
+
+func TestRender(t *testing.T) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	descs := protokit.ParseCodeGenRequest(utils.CreateGenRequest(set, "Booking.proto", "Vehicle.proto"))
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, Render(descs, "json", buf))
+
+	var tmpl Template
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &tmpl))
+	require.Len(t, tmpl.Files, 2)
+
+	buf.Reset()
+	require.NoError(t, Render(descs, "markdown", buf))
+	require.Contains(t, buf.String(), "# Protocol Documentation")
+
+	buf.Reset()
+	require.Error(t, Render(descs, "pdf", buf))
+	require.Zero(t, buf.Len())
+}
@@ -11,10 +11,10 @@ import (
 )
 
 var embeddedResources = map[string]string{
-	"docbook.tmpl": "H4sIAAAAAAAA/+xZ3W/bNhB/z19BaC/bikodugDDQLvA7LnF0GZB3O2dls42MYrUSMqNoel/Hyiq+v5a7bhZkJcguvvxjryP39ESfnMfMnQAqajgM+cH95WDgPsioHw3c/74uHr5k/NmfoWJ1NRnML9CCGuqGcxvpdDCFwwthR+HwDXRVHDsWe0VQkkiCd8BcleUgUpTs1SBb1BGXRhKEveGhJCmlbVmdUQkQe4SlC9pZFZlJip2P4BSZJebLo0jGsycJHFXMWPWsGNNVj2+F3zX4XXIr9HRLXLfEbWiwAJVyLEmGwZoK0kIM4cwVjgsXGKfEaU4CVveSwWyZhsbMiZ2UsQR8gVTM+fHinGEsBFG4BvlJxro/cz53vFORrxyr8dBr5sQvQcSVCUIYSk+1SUIYeBaHufZabFnH7ohH48RDCPekw2wYUglk51A7DX2iL3WQbDeiKCxrlLftWoYPXil4If2jRnlfyHzB3hZ0SYkpqLzKrKP2DOw+bA9s8JEa8xvVuXfUh7APXJ/z+KmkBNAJMEnGgLnnwC2JGYabQlT8F2aYgijPVFUzZcFysVeIU0S4EGadraW7amltfgnYbE5jsHNc9nPKEmaei8D5GanJdXkK4PXE11PK/ZsqxV84WWdXXJD1ULBBr/ea+CGP8/PCDegNASo9DBCDteXIIfHQR9FTE6lkF+IGkHcxOEG5FdmmY4qG43RV2Katr2F4JpQTvmuYblU/Hc2s2kZp7MnxDrYq92gqqqyUHgcXu5OdC6my4I8Rm+vL0FvJ/KSOdv/gE5svM9OJae15aWaqqeN8oe+sV7/9VKUt/l585LBAVj/nMaUb4UMCRvsludJ/jzJnyf5k5rktb6fQj55jaxBHqj/Ze82Hn6Gd8zvD6D34nG8vHhwwrJnReOT/g7+jkFpNE5dd6AiwRVMgD44P+WpvAA55fFpMEkubdGIbfVcu9YSSEj5Lk2Ryv4faufBPdjItzZhxb27sOov3MZjvP00FBVe6nxtu/YJI9Le27Oqrff++I2n/74z1tMd+usxwHn17YZqZSnPtBtJoUV/W3++mAhtAtgPWLx4MaT+jRzIkP72qPd9fGFlb8Wg+28Grb+7HVLfxZtjh75R2i2aapNUOR6z4qtTVF8KPs/M7INFpcMrz0ObN+RmkjOKWkTRJGsmVZOANmeToG+nHWSx3hMZjTveTzuJyWsvsEVcTdpqkFadsjpuThV2usJe8UHq3wAAAP//9p3zJMIaAAA=",
-	"html.tmpl": "H4sIAAAAAAAA/9xa23LbONK+91P0MJlyThRl2U7yK7T+qnGSSW1NElfszM5ebUEkJKICARwCdOzV6t23APAAkiAlnzJbW7kICTS7G91fNz5ADn96+/n04h9n7yCRKzrb2wvN/wBhglGsHgBCSSTFs7OMSx5xCm95lK8wk0gSzsLAzBrJFZYIogRlAssT7+vFe/+1V0xRwr5BhumJJ+Q1xSLBWHogr1N84kl8JYNICA+SDC9OvETKVEyDYMGZFKMl50uKUUrEKOIrJff/C7Qi9Prk6zxnMp8ejccvXo3HL47GYyIRJZEXFEa1KfMMMOfxNayLF4DvJJbJFF6O8epNNbhC2ZKwKRzgFaBc8nom4pRnU3g0mUzqQeWgb5yZgmfc8V6AQEz4AmdkUYumKI4JW/pzLiVfTeGoNrvZKx6SA8s/rfs7JstEToHxbIVorW3OsxhnlbKD9AoEpySGRwihfqPj0TG+6pqdWGbvQ7MVx9ExXsG4a/LwL1kpsqwq0PkxjnimgawsM9zN9/HLV3hy3NEk0ZziLpoOxuOfW/AQ5F94Cq/t8WJNEacUpQJPoXzqmlFl2BeqV+OxpRNF35YZz1nsl67HkfrX1akLQWZTJhM/SgiNn+BLzJ7aIOgqW8zVv66yuIOdRpKiKOokqcgOTBwZkjGk7SQRFmMmdVF2EdbFllJhre3gaZ++8RsInsEnDmYAOIMFyYSEFAhTap4Fbd3BM7jQmecLWBBMY1ELjfSAb5Ah45YL6tP3SqD+wEKN3Qy2aZsU2i6uU3xnZYeFst/QHFOHtpc3UXZUKHuLRZSRVJWVQ6XdV52BxVcSM0E4s4NbDQ4F+F0ptGtcBrXeJtCDCstg/4LE/SgsA/4pX81x5lB5fFONx/eUQpav4BLRHIuRnUSWr4by9wmtdg9Mj67JtpjcSNvh/cRDRIiizEREk55GWMysr2d9PVu6klm9Kyna/qGDOdi2Is4kVsSptvBI8shX44gwnEFOLbWUCOlroqRNt/fBcmOleNFuwZQw7JdeHTR2OEd3rj2BGVACs8Zu3NjY5pzGriW+JxSD2hEJW0JMLhu9lypfzNSWbTkmIqXoemo28RtTjXJtR4rZdBmOyyEHw2rHuemUH2FKh3V2uAyiZMmmkKkY7qjXQk+CYf/j/gvYf7cPiMWw/8c+zFG8xEJvhgmGC35qBVzPOSI9emlDpEJHc7hyijANojnl0bc3ez3Ian5rrzXCTOLszXYUNbjYSwWGDtF7/X9zdPR6mFAtFuPotfVtBXPNZ9ShwTz5jTpx0KImm6qgl6GY5EKV2VUz+WFQHGXM20++D18FziDKheQrOD0/B9+/xUmrlhipUX1uCgNz9lOPiiqWRpMDIPGJp897Xu9xMDmo5CezqiedFj0pDJJJOa8KWCu0e5NXntbCnJaz1RjAep0htsQwUq1AbDbVhJp6rOrjn0ztIdMTGKnNpCERUjKzXgFCVITh0XpdiHuz6jEMUEs8p80By5+PWAi0bLnUY9Zh/H1OaelAKFLEIKJIiBNPl5k3+xgGalQ59xtnyx4HDVK65tZrzOKOZ5Xv71i+eijH3z2o4xVRvJ33NWA2G79mne6V/FGsRCHPp/gS05puivta0TnOLkn0YDA6r7NxD5kIg2ZBNL9rf6H8r53tUh5vdm5I0u+aJCnSrcNqa60thkFMLotO0tMUhhuCbj9FdOx91Wo2YTLRLcjdHJKJtZyiKV7w1Ipo4WPpTQoji0Vuqt13qIeEyWHpgp3bVjUlh3bY++yoObKA0Qck9EG0CbLQMM4qItURz2s1QVnfDNqj2SyU8UwrDgMZ6zeVw+pFnzCrN8tDMxbIrGUocFgKpdmR2uCsANBZV+2fq3hkbKdUdtZVCnWqTC3NyoR5NXDt16KEVRS22EpnOlFPCIvxFYw+6ygJ8GKcZjhCEsfev2O8QDmVsEBU4KebTShkxtly9raSGSnOoMfKUlyvm8Ao8PDWqNJlt9kUb1PQ0vZMoSUM0p5FdnPY1zc6WQwDjbXZXnNDX5mK0NXb2ux9sHNexKhlaL1+zM2RraOgKAb8J4zAu0SUxEjyzFxteNUIHmU5xcKD9gqSo9nvhUhsrnUUtTlqg9WsqZ1iVwENQrSuqh6BwhezE+2aGmeBbSuxMiVFqf2dyMTEvhPfLWvaqewcw04a1vTxSVEyUGT/6ehL3maLDYWU1O5o4BeId22EpSnXNl6oC9wO3q1onGVjFU7reyqwA7MmafCdyEQtc7MBXrTgB8OuiupQij/Xe8D/Cmp1A4c0I0wuwPv5+aXXheR99NEbQqL1vR4B3xorZbp8oYdoh/OGn00K0bpmvBGNqOy5qcQvSNQv5p7vgYnF8Enjv4JcNLScmvM0YcuWvnriRrTFBHkH2DcZhotgwF/MMNzftbDfeza+F0JeR61RMNUVtNesKhdaTZ1UzfUWheAoA1cRVJHQOerC3wn+HaC/E7B6YNUHkC48uuDoQKMFjA4QhlpgjYbeq4Se6wIbIbv3zSEsPGDPvClUBrrlXeBy1z75UF3yLlC+3w75IAXQfzXV3wx/dCP8iGXCY2j0wy/4zxwLCY0y+IJFypnAzdH7LgDjzgOiv1hbC7bFaBOzBmHF1LnMMFoRttxsQOjnClE72jXh6xg2w27LZu42pn9U67cw+lgYnLevDKwbB5Nd15XDwIWDdd1g/qBthFIySqRMvaaTyVGB5uKc9uHi4gzmhMWELTuXDK5jWj+1dga5XToDQv3zZ0hKnPUd49T2w+Pr3fK2Mz2vDndFxsqqGzzdrdeP+38ggltcIgyU9GO2nf8Yn7cIFdHdIqVC7BbZlTbflko7bxw6QB66cPhROO6/bfjRQLzTVnCnC4ZdWuUN8t74sn2pYM83iEX528quvwMlE2O2yRT6/nCm/hW5lcKSL4zSjEveJAGfuMSiejt9/rx6/hu6RNXL2bVMLJL9K68/eVQLfTirKUc+v+6wiha42rCqWZdeYfunmqykXfpX+HIj3nNAyRLoYqGEm1r4wPxpmm7RoAK0RcSEbYvQr9tcPT1PUJYOmUm2+arS4RZpFkYT2o1ysAohDMxwGBR/Qf+fAAAA///J6JHwUy8AAA==",
-	"markdown.tmpl": "H4sIAAAAAAAA/+RWzW7jNhC+6ymmUg9rL+TcF7YPjZtdFLtpsA56WRQNbY1tATKpipSRQOS7F/yRSFlW4gLpqT5Ymhlpfr75ZsQEHiom2JYVsGLb+ohUEJEzGs0JUHLERSxYGS/nN2QZRUkCj2RTILAd3DIqkAoeNU1F6B5hdpcXyJWKmubnXV7gX/p1+LSA2T05olIp/Ggad//nh6S7n0QATZNCvoPZN+Sc7JGDUkbrPLdqpQCsm6+M7kNXd3VRhO6QZs5FCkgzSDtJh/mV1sfzGEb3bgGeBVKeMzqI0hlcKA1aWuAJC/A2E9KDqFSKne2a8GusTvl2AGOr9lX+2wpbbQo/1ltSkAr+IEWN8PhSos6aG2V60spUaOUkupognnJdZo548xJIke/pIq7y/UHEyzmBQ4W7RZwYdj6yUj83vyktSbv3o6aZrZBvq7zUpFYqyMaTqhfYw+BZbzx6Rlz0qnH/QvhdjkWmfUowtyANOCDhK9lgARKCN0FGElL9A3uFvuh+IEMMtX/Xb+krBdnRVscLm2rliX3aZGEeNyl/yGmGzzD73eTDIc6wrHBLBGaxzHBH6kLAjhQcJ0pNp6vOOptOW440DWWbCvqQWEBW1oMhiVLgxE9gAAxNzpWp1FKsvXpow9mJpB8XD/EvhOvLfX3cYDUG9RDuDu1x2IOBvgR9D3kr6/1IcprT/bnFpuek/xg6a5r/lNoZPrb7NU2XwSi43fcOcyBBm97qwRWAm+JGwX4LwgCMIQhotr9D4BK3AhhGlnAIycUN/v/l59OAoE+jDPU96bXgjJ3+s3UdQV8h5zcUB5a1HP2Of9fIRdud78hLRjm28mh3zhtxLp7LMvzo6ATG97dL6XyNO3WwzW0TnH4tKiTHnO6VAm7uO8idV1vZ0K3VX/BrDa85fmvwBj3m7anEtdfNZ5LA8CyhmzUr9cm07cY9E8hBwu3HjyDhN3IiIOHhRRzMgH1m2pRo1ZcH3ct68zLWNHu9OFv+z9sDIpo0ffNCNppTtMUwhpsl9FWu07qEbv7KMrTpgkLZVhZqPvd83a4PpCq7pw89Z7r6Vu6A/icAAP//o9X/quoLAAA=",
-	"scalars.json": "H4sIAAAAAAAA/9yXzW4aMRDH7zzFiFMqBZDSlEa9JZGQOOQEOUWp5GVnvW6NTewxzaqq1HfoG/ZJqt0F1gYvpChVk9zQfHg9v/nPWNx1AL53AAC6C6NJT4sFdj9BN9Uukdg9rV1KE9rSvDbMFot45MzG7XxzciY1o+H52vGFLVk8ZZEvgpyNvaBcq6jLuKRYO0aVowPw47SlxiB1X4lBYFNhYN4q8P1ZrMCwjn9dn1DeNTb13Vq0sGRGsERiT6LilAOqmU6F4n0YK8wyMROoCDJtNh5QyBmJJYJy8wSNhd8/f4HIoNDOQCZQpiAsSPEVZQGkIWdl7DppyaRDewrOItjqYiCUJWRpPwI8uHkDXKgI7iDWh+1Fe6iFIuRo4rC9FB/1leDKzUEbGInH8tcJs2DwwQmD6btDPWi0/sJ6MDw/0IPm5k0PpFY82oT4SPvhu10YWDLCC9huxsBP3+3IXvLuGPlHOLgWMbqoGt0zy/EAgaM06Y4RZQuZmERcXCPu/4jkKET2qN05EVxhCkJRPWt9mOZoEebaIGxGWhZ1Cu6OM+VMgUHuJDNQXcG+7fVoj9qPz855eN7G+VWvwEw8YhqR8aX8xgoLWflqJAWh7cNNgK4G5FbvdLZ6OYAZBJ0RKuAGGaGp484+n128vM35zEqtWEakumKJgud0CGb54B6G+WH4epft/mF/uh7f9tL7Cy29wa2UaC2f8q/Lj2vK9K08eqZfZWlHpiKFbnuCCrecfoFT4/BaMmsHIyZt/XN/twOSTa+hdsDcWQJWd36mFTGh4HY66l2sXq+01NjHXiIILifX4zEQPlJMF+GHGmKhnbdczKc2CZuft3wiZGbJDJwS5ZVj3Ooz4aQqbf98VMrfAXbDig0fpgpgJhFkmCnA4oNDNSvXafvUtNG5KggnLYTu7svjYoR2s55OaR+dqsO9i6vxtEbUue/8CQAA//8z+wC/ohEAAA==",
+	"docbook.tmpl": "H4sIAAAAAAAA/+xZ32/bNhB+919BaHvYVlTq0AUYCtoFZjcNhjYzkm7vtHS2iVGkRlJpDE3/+0BRkvXLopImXVvkpajuPt6Rd/d9ZF38+jZm6AakooLPvZ/9Fx4CHoqI8t3c+/PD+fNfvdeLGSZS05DBYoYQ1lQzWKyl0CIUDK1EmMbANdFUcBxY7wyhLJOE7wD555SBynOzVEFoUCZMHSjL/EsSQ5431prVCZEE+StQoaSJWVWEaMR9D0qRXRn6GBzRaO5lmX+eMmYDezZfM+M7wXcDWcfyGh/dIv+CqHMKLKrymrBkwwBtJYlh7hHG6oR1ShwyohQncS/70YFs2M6GTIidFGmCQsHU3PulERwhbIwJhMb5kUZ6P/d+8oJPRrzwz9ygl12I3gOJmhaEsBQf2xaEMHAtD4vitDiwH8OQD4cExhHvyAbYOKTRyUEgDjp7xEHvIFhvRNRZ15jv1jQ4D94Y+LF9Y0b538j8Afw40aYkZqKzLIItSZlGxTQZM/JXVCWMHMyHGWyzdjGexAyjKWG50rWnggE/UB7BLfL/KGqqkBdBIiEkGiLv32pXW8IU/JjnGOJkTxRVi1WN8nFQW7MMeJTng7QrsvkrG/EvwlJTM4NblLZXKMu6/qAAlGGnNdwwu4A3bDjotBwHloaVBQcF6xezoQi1Ury51cCNtj68WlyC0hChYwaHcJx9DuH4MqSlrsmnystvRDkQl2m8Afk/K9DAlDlr9EAqVKvPRMHpx1sKrgnllO86kY+OO+YwRyva4jrcN6U6OGi9rpqualC+B57G6NUc+W94Gn++h9NDSV5RbZfOvfQc4uKWn0cXKFPer0BXbL3rNjqPNVFTituxmEX/Am7zvOZr+QVMQcOa52O8GifztLq02XIfKp4g32z8MVAxrw5paWb+wfScwQ2w07c7pnwrZEzYKLWe7v+n+//p/v+m7v8W76eITzkj1yBvaHi/X0uqQWkp0GDx7nvhD1z270HvxZfxc8ijC5Y9K3I/C67gnxSURm7pugKVCK5gArTRwWmje1d9KltZj4ezHo2JuJuYlPXpKElp7cmIpXrpvdYSSEz5Ls+RKv5eEunue7CV723Cmk/uwrrvuY1BNk7r5qO9fjqOyjyrnkTXIWFEqjxvv4NKgbBeVLxBiyFuS4H7AXT6+eOi+ID/zAV4WH+fX72mlY33E/ND/GmWl7BLoUGNAZbPno25fyc3ZMy/Puj9KfmwtrdizLv8bsy7vliPua/SzWHA35n0nmr1Net4W1ajOaEF1RVa/I9Ig/CN77HNG60zzXGilkkyKZpp1SSg7dkk6NtpB1le74lMnLD1ftpJTF9PAns6dpSdQQ1rK9jAQ+ooVrVS4YBITUMGi9l/AwClE/ENKhsAAA==",
+	"html.tmpl": "H4sIAAAAAAAA/9RabXPbuBH+rl+xx+TGl0tIyrKdpDKtTs9OLtO5JJ7YuV4/dSASEjGBQB4BOnZZ/vcOQIDvpOTXTi3PmAQWu4vdZxeLlb0fzj6fXv7z/B2EYkMXk4lX/AXwQowC+QDgCSIoXpwnkYj8iMJZ5KcbzAQSJGKeW8wWlBssEPghSjgWJ9bXy/f2W0tPUcK+QYLpicXFDcU8xFhYIG5ifGIJfC1cn3MLwgSvTqxQiJjPXXcVMcGddRStKUYx4Y4fbSTdX1doQ+jNyddlykQ6P5xOX72ZTl8dTqdEIEp8y9VClajiGWAZBTeQ6ReA7yQQ4RxeT/HmuBzcoGRN2Bz28QZQKqJqxo9olMzh2Ww2qwalgnahzBysQh3rFXDEuM1xQlYVaYyCgLC1vYyEiDZzOKzE5hP9EO7X9FO8v2OyDsUcWJRsEK24LaMkwEnJbD++Bh5REsAzhNCw0KlzhK+7YmeQPSjnmh2dI7yBaVfkwf9kp6gmVYLODrAfJQrIUjLDXX8fvX6DZ0cdTgItKe6iaX86/bHioVzIyb/xHN5Of+zsyY8oRTHHczBPXTEyDIdM9WZaGhZgifxv6yRKWWAb1QNffro8VSCIZM5EaPshocFP+AqzF5CNMVst5afLrK5dsa+Gk3zf7zhJewdmPR4SAcQ1jspJhAWYCRWUXYR1sSVZ1Pa2/2KI3/QY3J/hUwSFAIgYrEjCBcRAmNzZz26bt/szXCrPRytYEUwDXhE5asAukCGClgpS1HtJUC2ooaaeDLZxm2lulzcxvjezA83sN7TEtIfb69swO9TMzjD3ExLLsOphWc+rvYbF1wIzTiJWN245OGbgd4ZoV7uMcr2LoUcZGmP/gvjDMDQG/5RuljjpYXl0W45HD+RClm7gCtEUc6da72CWbsb89wltdjfMAK/ZNpvcitvBw9iD+4iipLCIKnoaZilmbTVrq1mjSlLLXaFO+wd19Xtk+RETmIm6hGci8m05jgjDCaS0xpYSLmxVKCnR7XPQHKwUr9opmBKGbaPVfuOE68nOlSawAEpgAWjoYFtGNKgW6geVPykGeSIStoaAXNVMuCJU6lJMZW3/NI/lgPCYops5KCN3juVtpYbZ26GsbLoVTp9CPRVW285NpWwfUzrOs1PLIErWbA6J9MeOfPWDjNwQw97HvVew924PEAtg7489WKJgjbk6DEMMl9FpzeBqrsfSTu3EqDDbGi6VIkyBaEkj/9vxZABZzbX1vfqYCZwcb0eRnipqsdcSDOWEKXDe/mWJDt8ej9VAwWo19d8eTzpQKOoZeWkonuxGnPSURc1qypDYCQpIymWY1Soj+cdz9VVGjXo/2DZ85TgBP+Ui2sDpxQXY9h1uWhWFI0ddycJzJWwXcpeeLBWN0HAfSHBiqfueNXgdDPdL+tmizEmnOid5bjgz8zKAFcN6btLXRQAvpWa2HAPIsgSxNQbnPaGY5yYy5CfLnsv4+BeTZ8j8BBx5mDQoPEoqTvLjIW2GZ1mmya1F+ei5qEWe0uZATZ+PmHO0bqk0ILZH+PuUUqOAx2PEwKeI8xNLhZm1+Oi5clQq91vE1gMKyl/P7YrLMsyCPB/S/R1LN4+l+LtHVdxUMnfUvgJMnttlWcT7d/KH3olEnk3xFaZVuckfakcXOLki/qPB6KLyxgN4wnObAdFc116RZWQFzoUqdXieS/dUuncrIGtRkMLvckzV4MrKSkiTd6WH5wbkSueXgVQxniZUUtI2q5+2tRTkhTOVmPpTRjir7UqnyssortlZ62i0icGp1ZZ5eSaPZRYvPDAq1D3eirHwwEgZk2Mc8wFxdT2ty5G9P5XBS4uUF7+aQeSvJ6p+YfXjiWThiWChGHuuCNSbdGX5ou6d5VtNw2LMFUlLkNsjyRPFOWXeWxbs2ZdZ1+JuhoO6S0UwRNSJPbm1whMBXqGUClAekcPgnBVFj3yRbNFijLX0pTSNXrRFj3ihnPiTbGRcg/NZWZCDFeA4wT4SOLD+YzRaIcrxizz3uEgitl6clTSOrDLUmAneLGuCRmPlrGClIjPP9du8OSkPlb8xP4ySPG/YqWdeGqzFVNonyzDlOM87k6V26o/nxgOm7MJnKJF1AOS5CuY6l5ilzzdFMKrE0WCSZTbU4aZd0BKUZc8jNdFloOMQ/wkOWFeIkgCJKCl6LVY5gp0kpZhb0FrrhYeL3zVJAAXgPTc8bO7e03sCaI72xe5odFQBPUCgdZEo3N01vbG9LbqNSwqz838QERa2B+cRIr5nuLcubOr4k45I0N5/4XxJ2+Vr/SPPxlIdGVdOGRWUDInqwhqgfT42f+4XNL1hUwuc1noVyU3K8LA4GTh8JyKU28xziHT2fzTsyrQ+5uLP1fGzk23+D1CrzgeIE8LECqwfX15ZXUg+RB69JSRa69UI2LUxQ9MtVarKu8HCWyaLweql1fe8VQVTyuuvYmRDt3wpGo+PXNMMGMCsbUm4LV6G65qyntlewjS4yAYAIoywdYtfNbE7Z7kJZeQdYN8sYPrqF9CvKtE2p56kwuhfZ94mrcQhW+iqemjf2h/kUjAQOWVzvBE0fSFjAqbMsneIiJ546IuGMhbU4diNA08k2iDKaMUZWpSk5tbXygE7BklBQlaFM5wP+DrPS0jqt7JyNUDV/hzm2IPWIdx1UWfQMoK4Ft7MiskumbXC1mDLZKAtUsfb7ul4DFkl94dPxbcFXq+tzLqk3807YOu+6fexku9OqfdJEu+jBMBwC244tT51Wv2IRRgF0MiuX/CfKeYCGmHwBfM4Yhw3Rx86AAp1HhH9em8t2OrRJmZVRjZTFyLBaEPYOs+Bq2ft7p3lFubrCC6G+yUXc3cR/VSpvxzJsue8wHm7E1FrZBTe7etkjPQxal2M4h/3HBQTR/5Hn9Wgkx0LDZ/i+vfh8vIcloTJdmund9F3++uLkxHotUNnhGh4/hwJgZOh26EIFr9Ewc1ufuuJtfFoMx4zUTd6acyy58NfhN2lNzES0krSGL6zTOu8hUhbdwuVNHGe72bkvvDoH+sETefAGGhkdIA81sd4KhwPNzGeGoj3Ogru1bfo7vRefm+sbPcq6vPmedL3rZf+8mjX77vkt+XdwqG7ulVGtCFlygcnlt/aN2uCT5HAvHw7ffmyfP47ukLly/mNCE3VIILFr1H5ePqsfDz/cF4+f0mXN50io4W1NsoMwiqDTfruc4765wNzLk96kFUj6ELDoE9ufGT+NI63cJAG2kJSmG0L0a/bVD29CFESjxCch9t0le7oJ2nGSR3JregwcVGReG7hP88NxYYuJpP/DgBv0/7mUTAAAA==",
+	"markdown.tmpl": "H4sIAAAAAAAA/+RXS3PbNhC+81dsxRxsZyjfM7IOtep4Orbrsd1eMp4GElcSZkiAJUiPHAD/vYMXAb0St01O9cHA7pL7+r4FoRzuW97xBa9gxhd9jawjHeUsmxBgpMaLUceb0XRyTqZZlufwROYVAl/CJWcdsk5kUraErRDGV7RCoXUm5bslrfBP8zp8uIDxHalR6wI+Sen3zyf5sD/NAKQsgC5hfItCkBUK0Npqveeg1hrAubnhbJW6uuqrKnWHrPQuCkBWQjFIJswvrK93Y1jddwuw6ZAJytlelMHgQ5mmFRW+YAXRZkPGJmpd4GB7S/hHbF/oYq+NQR2r/KcVBq2UNsyCVKQVFlq3hz9I1SM8vTYonk9yYZXFi1EWnVGeBg9vpk0k4pCvp+OkAVLRFbsYtXS17kbTCYF1i8uLUW45+8Qb89zkvHHUHd7PpBzPUCxa2hiqa+3KmfGF+P3hRuss+2SaAWU6EM8nUsZHDtQRSbqVcmxrnKIcthh2JJ9bsnmkX4w9uyUbWvc1CPoFP5iXB5vP479VYN69JuKKYlUaKBTYLSiLJCi4IXOsQEGSJahMQWH+QEGyDGvhpRRp499zVUU8QJmZLnFJ+qoD2xgbdzyjoqnIqxFSljr51LmwqfkHrStbzgllJW5g/JvNVcCoxKbFBemwHKkQaUkqgadan53NBuv47CxwX0rG5y0cpIrzYNmuNXjRIrNt8q5AJaOz2/Y497b1gxjb/zMRZrnr6zm2x2DYh8IvyWYPkhj7ICyepq7hTjbnPqGMstWuxaXnpR/cOruByU+FO5tqP3tQFNM4ku+Q9bU9Svzh/p0G89+NmQLj9FswvgEz27pjeNGlK3t8jRutB1S8hJXARJs0+TBkoL7SdBNm6PghLifdPvIxSzt/8Ev4/52Hz3sD8fnoRERMtiDYnobhVvDWOfghM3CL3ZqXYRQe8K8eRRdwfUDRcCYwyEdx3YVwV9yVE4RdAofhNVcin1IE0313vDr5/Dj4vP6xa5HUlK20BmH3AZrg1VW279bpD/h1hq85/tbI7rFDeAYEYgyTndzmbGPyHPYvdOYIGzfm10LA5453KEDB5fv3oOBX8kJAwf1rt7bD+pEbU25U1/eg4KGfvx6D0a1eCkqHYvwX7Qmpk6wNnCmz7S8bk6rWIzifwrbKY29KCMJl06Q2U1Aqu8pSzcctX5ePa9I2Qbpfbzkz1Qd5aL2UBSArtc7+HgBtI+L8iA0AAA==",
+	"scalars.json": "H4sIAAAAAAAA/8yVz07bTBDA73mKUU4gkURK/PlDvQFSpBw4JZwQldb22Nl2Mxv2T4pVIfUd+oZ9ksoxhBjPJjQCkZs1s7P2/Oa33tsOwM8OAEB3abTTs3KJ3S/QzbRPFHbP6hRph7YKPwfS5ZJfmVo+Xmx2zpUWLo6eE9/ESvAly/nmHeuaTbx0c01syvikfE6M1zUdgMezQI+N0l0tNhamlg2/anA05BpsVHx4f5LcaNjq78aihZUwUiQKewqpcHNASnUmqejDhDDPZSqRHOTabDJAWAgnVwjkFwkaC39+/QaZQ6m9gVyiykBaUPI7qhKchrlY4UvRSiiP9gy8RbDrDwNJ1qHI+gzwxpe/AJfE4JYUgC2JQy3JYYGGhy2JRX0pC/IL0AbG8qF6OhEWDN57aTA73TeDODrSGcTRnhnEUXsGSlPBDiGOuCFsL29PYWCdkVQEhzHYLm9PZCd5f4j+DAcfkNGzNvp31nEPgYOc9IdIGSATRwwZ3hH/OZIchMgeIg9MZUGYgSRXn7U+zOZoERbaIGyOtCpr37B9nN1cEBgsvBKm2mY0tIGjORq2ubNCvrOPH8A5jj6dcxyFOMdRmzOv9+fYvZNuLh8wGw1beC/UD1FayKtbIykd2j5cN9DVgPzTPZ0/3RwgDILOHRIUBoVDU68bfh2eH9+fM8zsIFPXLOMoxBJlMXf7YMbRW2D+F4dgxhEDk7fRH6GO9u0+MgACMrEuHbdK9h9c4kHEURsE78ERapBorVqtM31ur0stFy00F93usoqjIKbR15lGh6+S2w3OjMcrJawdjIWy9ePuaTdIvswaasSw8NaBqCefanJCEtzMxr3zp9srq35X//cS6eBiejWZgMMHx3nRfFFq+Xih+fg2tWkjswVtlxXWmYEnmeoMOW71nnCybm33+Vib3wJ2LcoNH0ElCJNIZ4QpweK9R0qruyl8akJ0LkuH0wCh27tqO45Qu+rtlHbRWU+4d345mZ12OwCPnbvO3wEAM/sAv6IRAAA=",
 }
 
 func fetchResource(name string) ([]byte, error) {
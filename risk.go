@@ -0,0 +1,47 @@
+package gendoc
+
+// The values of MessageField.StabilityRisk.
+const (
+	StabilityRiskHigh   = "high"
+	StabilityRiskMedium = "medium"
+	StabilityRiskLow    = "low"
+)
+
+// lowTagNumberLimit is the highest field number that's encoded in a single byte. These numbers are usually taken by the
+// oldest, most widely used fields.
+const lowTagNumberLimit = 15
+
+// resolveStabilityRisks sets MessageField.StabilityRisk for every field in the template.
+func resolveStabilityRisks(t *Template) {
+	for _, f := range t.Files {
+		for _, m := range f.Messages {
+			for _, field := range m.Fields {
+				field.StabilityRisk = stabilityRisk(m, field)
+			}
+		}
+	}
+}
+
+// stabilityRisk rates how risky it is to change field, by counting the signals that clients depend on it: it's required,
+// it's part of a request message, and it has a low (single byte) number. Two or more make it "high", one "medium", and
+// none "low".
+func stabilityRisk(m *Message, field *MessageField) string {
+	signals := 0
+	if field.Required || field.Label == "required" {
+		signals++
+	}
+	if m.IsRequest {
+		signals++
+	}
+	if field.Number > 0 && field.Number <= lowTagNumberLimit {
+		signals++
+	}
+
+	switch {
+	case signals >= 2:
+		return StabilityRiskHigh
+	case signals == 1:
+		return StabilityRiskMedium
+	}
+	return StabilityRiskLow
+}
@@ -0,0 +1,58 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+	. "github.com/pseudomuto/protoc-gen-doc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStabilityRisk(t *testing.T) {
+	required := newTestField("id", 20, descriptor.FieldDescriptorProto_TYPE_STRING, "")
+	required.Label = descriptor.FieldDescriptorProto_LABEL_REQUIRED.Enum()
+
+	fd := &descriptor.FileDescriptorProto{
+		Name:    proto.String("risk.proto"),
+		Package: proto.String("test"),
+		MessageType: []*descriptor.DescriptorProto{
+			{
+				Name: proto.String("GetThingRequest"),
+				Field: []*descriptor.FieldDescriptorProto{
+					newTestField("name", 1, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+					newTestField("view", 16, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+				},
+			},
+			{
+				Name: proto.String("Thing"),
+				Field: []*descriptor.FieldDescriptorProto{
+					required,
+					newTestField("color", 2, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+					newTestField("note", 30, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+				},
+			},
+		},
+		Service: []*descriptor.ServiceDescriptorProto{{
+			Name: proto.String("ThingService"),
+			Method: []*descriptor.MethodDescriptorProto{{
+				Name:       proto.String("GetThing"),
+				InputType:  proto.String(".test.GetThingRequest"),
+				OutputType: proto.String(".test.Thing"),
+			}},
+		}},
+	}
+
+	file := newTestTemplateWithOptions(TemplateOptions{StabilityRisks: true}, fd).Files[0]
+
+	request := findMessage("GetThingRequest", file)
+	require.Equal(t, StabilityRiskHigh, findField("name", request).StabilityRisk)
+	require.Equal(t, StabilityRiskMedium, findField("view", request).StabilityRisk)
+
+	thing := findMessage("Thing", file)
+	require.Equal(t, StabilityRiskMedium, findField("id", thing).StabilityRisk)
+	require.Equal(t, StabilityRiskMedium, findField("color", thing).StabilityRisk)
+	require.Equal(t, StabilityRiskLow, findField("note", thing).StabilityRisk)
+
+	require.Empty(t, findField("name", findMessage("GetThingRequest", newTestTemplate(fd).Files[0])).StabilityRisk)
+}
@@ -0,0 +1,58 @@
+package gendoc
+
+import (
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// minScalarSizes are the smallest encodings (in bytes, excluding the tag) of the values of each scalar type. Varints
+// take at least a byte, fixed width types always take their width, and strings/bytes take at least their length prefix.
+var minScalarSizes = map[string]int{
+	"double":   8,
+	"float":    4,
+	"int32":    1,
+	"int64":    1,
+	"uint32":   1,
+	"uint64":   1,
+	"sint32":   1,
+	"sint64":   1,
+	"fixed32":  4,
+	"fixed64":  8,
+	"sfixed32": 4,
+	"sfixed64": 8,
+	"bool":     1,
+	"string":   1,
+	"bytes":    1,
+}
+
+// estimateMessageSizes sets Message.EstimatedMinSize for every message in the template.
+func estimateMessageSizes(t *Template) {
+	for _, f := range t.Files {
+		for _, m := range f.Messages {
+			m.EstimatedMinSize = estimatedMinSize(m)
+		}
+	}
+}
+
+// estimatedMinSize returns a lower bound of the encoded size of the message, counting the tag and smallest value of
+// each required scalar (or enum) field. Other fields contribute nothing, since they may be left out of the encoding
+// entirely (e.g. proto3 scalars that hold their default value), and so do message fields.
+func estimatedMinSize(m *Message) int {
+	size := 0
+	for _, field := range m.Fields {
+		if field.Label != "required" {
+			continue
+		}
+
+		valueSize, ok := minScalarSizes[field.Type]
+		if field.TypeKind == typeKindEnum {
+			valueSize, ok = 1, true
+		}
+		if !ok {
+			continue
+		}
+
+		size += protowire.SizeTag(protowire.Number(field.Number)) + valueSize
+	}
+
+	return size
+}
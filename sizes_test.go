@@ -0,0 +1,52 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+	. "github.com/pseudomuto/protoc-gen-doc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimatedMinSize(t *testing.T) {
+	required := func(f *descriptor.FieldDescriptorProto) *descriptor.FieldDescriptorProto {
+		f.Label = descriptor.FieldDescriptorProto_LABEL_REQUIRED.Enum()
+		return f
+	}
+	repeated := newTestField("tags", 5, descriptor.FieldDescriptorProto_TYPE_STRING, "")
+	repeated.Label = descriptor.FieldDescriptorProto_LABEL_REPEATED.Enum()
+
+	fd := &descriptor.FileDescriptorProto{
+		Name:    proto.String("sizes.proto"),
+		Package: proto.String("test"),
+		MessageType: []*descriptor.DescriptorProto{{
+			Name: proto.String("Thing"),
+			Field: []*descriptor.FieldDescriptorProto{
+				required(newTestField("id", 1, descriptor.FieldDescriptorProto_TYPE_INT64, "")),      // 1 + 1
+				required(newTestField("weight", 2, descriptor.FieldDescriptorProto_TYPE_DOUBLE, "")), // 1 + 8
+				required(newTestField("name", 16, descriptor.FieldDescriptorProto_TYPE_STRING, "")),  // 2 + 1
+				required(newTestField("other", 3, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".test.Thing")),
+				newTestField("note", 4, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+				repeated,
+			},
+		}},
+	}
+
+	tmpl := newTestTemplateWithOptions(TemplateOptions{EstimateSizes: true}, fd)
+	require.Equal(t, 14, findMessage("Thing", tmpl.Files[0]).EstimatedMinSize)
+
+	require.Zero(t, findMessage("Thing", newTestTemplate(fd).Files[0]).EstimatedMinSize)
+
+	// proto3 scalars aren't encoded at all when they hold their default value
+	proto3 := newTestTemplateWithOptions(TemplateOptions{EstimateSizes: true}, &descriptor.FileDescriptorProto{
+		Name:    proto.String("sizes3.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptor.DescriptorProto{{
+			Name:  proto.String("Thing"),
+			Field: []*descriptor.FieldDescriptorProto{newTestField("id", 1, descriptor.FieldDescriptorProto_TYPE_INT64, "")},
+		}},
+	})
+	require.Zero(t, findMessage("Thing", proto3.Files[0]).EstimatedMinSize)
+}
@@ -4,21 +4,65 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"math"
+	"math/bits"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/golang/protobuf/protoc-gen-go/descriptor"
 	"github.com/pseudomuto/protoc-gen-doc/extensions"
 	"github.com/pseudomuto/protokit"
 )
 
+// Tag numbers used to build source code paths (see SourceCodeInfo.Location in descriptor.proto).
+const (
+	fileMessageTypePath   = 4 // FileDescriptorProto.message_type
+	messageNestedTypePath = 3 // DescriptorProto.nested_type
+	messageOneofDeclPath  = 8 // DescriptorProto.oneof_decl
+)
+
 var (
-	actionRegex  = regexp.MustCompile("@action.*")
-	versionRegex = regexp.MustCompile("@version.*")
-	titleRegex   = regexp.MustCompile("@title.*")
+	actionRegex    = regexp.MustCompile("@action.*")
+	versionRegex   = regexp.MustCompile("@version.*")
+	sinceRegex     = regexp.MustCompile(`@since\b[ \t]*(\S*)`)
+	titleRegex     = regexp.MustCompile("@title.*")
+	typeRegex      = regexp.MustCompile(`(?m)(^|[ \t])@type[ \t]+(\S[^\n]*)$`)
+	deadlineRegex  = regexp.MustCompile(`@deadline\b[ \t]*(\S*)`)
+	maxSizeRegex   = regexp.MustCompile(`@max_size\b[ \t]*(.*)`)
+	sizeRegex      = regexp.MustCompile(`(?i)^\d+(\.\d+)?\s*([kmgt]i?)?b?$`)
+	statusRegex    = regexp.MustCompile(`@status\b[ \t]*(\S*)`)
+	categoryRegex  = regexp.MustCompile(`@category\b[ \t]*(.*)`)
+	stabilityRegex = regexp.MustCompile(`@(alpha|beta|stable)\b`)
+	orderRegex     = regexp.MustCompile(`@order\b[ \t]*(\S*)`)
+	idRegex        = regexp.MustCompile(`@id\b`)
+	docsRegex      = regexp.MustCompile(`@docs[ \t]+(\S+)`)
+	rangeRegex     = regexp.MustCompile(`@range[ \t]+(-?\d+)[ \t]*-[ \t]*(-?\d+)[ \t]*(.*)`)
+	embedRegex     = regexp.MustCompile(`(?s)@embed:begin[ \t]*(.*?)@embed:end`)
+	paramRegex     = regexp.MustCompile(`@param[ \t]+(\w+)[ \t]*(.*)`)
+	langRegex      = regexp.MustCompile(`@lang:([A-Za-z]{2,3}(?:[-_][A-Za-z0-9]+)*)`)
+	deprecRegex    = regexp.MustCompile(`@deprecated\b(?:[ \t]*->[ \t]*(\S+))?`)
+
+	hexRegex        = regexp.MustCompile(`@hex\b`)
+	flagsRegex      = regexp.MustCompile(`@flags\b`)
+	readOnlyRegex   = regexp.MustCompile(`@readonly\b`)
+	writeOnlyRegex  = regexp.MustCompile(`@writeonly\b`)
+	exhaustiveRegex = regexp.MustCompile(`@exhaustive\b`)
+	noSchemaRegex   = regexp.MustCompile(`@no_schema\b`)
+	retryableRegex  = regexp.MustCompile(`@retryable\b`)
 
 	scalars = makeScalars()
+
+	// dynamicJSONTypes maps the well-known types that hold arbitrary JSON to the kind of JSON they hold.
+	dynamicJSONTypes = map[string]string{
+		"google.protobuf.Struct":    "json object",
+		"google.protobuf.Value":     "json value",
+		"google.protobuf.ListValue": "json array",
+	}
 )
 
 // Template is a type for encapsulating all the parsed files, messages, fields, enums, services, extensions, etc. into
@@ -28,56 +72,143 @@ type Template struct {
 	Files []*File `json:"files"`
 	// Details about the scalar values and their respective types in supported languages.
 	Scalars []*ScalarValue `json:"scalarValueTypes"`
+	// The package that type names are shown relative to (see TemplateOptions.RootPackage).
+	RootPackage string `json:"rootPackage"`
+}
+
+// TemplateOptions controls the optional parts of building a Template. The zero value gives the default behaviour.
+type TemplateOptions struct {
+	// InlineEnumValues populates MessageField.EnumValues for enum typed fields.
+	InlineEnumValues bool
+	// MaxDefaultValueLen truncates default values longer than this many characters. Zero disables truncation.
+	MaxDefaultValueLen int
+	// EstimateSizes populates Message.EstimatedMinSize.
+	EstimateSizes bool
+	// StabilityRisks populates MessageField.StabilityRisk, a heuristic rating of how risky it is to change a field.
+	StabilityRisks bool
+	// MaxInlineDepth limits how deep the request and response fields of methods are expanded (see
+	// ServiceMethod.RequestFields). Values below 1 mean 1, i.e. only the immediate fields.
+	MaxInlineDepth int
+	// MaxMapValueDepth expands the fields of the message values of map fields (see MessageField.MapValueFields) up to
+	// this many levels. Zero disables the expansion.
+	MaxMapValueDepth int
+	// MaxFieldPathDepth limits how many levels of nested messages are followed by Message.FieldPaths. Zero means 3.
+	MaxFieldPathDepth int
+	// NormalizeWhitespace collapses runs of spaces and tabs in descriptions and trims trailing whitespace. Indentation
+	// and fenced code blocks are preserved.
+	NormalizeWhitespace bool
+	// Admonitions parses the callouts in descriptions, i.e. paragraphs starting with "NOTE:", "WARNING:", "TODO:", or
+	// "IMPORTANT:" (see File.Admonitions). Descriptions are left as is.
+	Admonitions bool
+	// ExcludeOptions lists the names of options (e.g. "deprecated" or the full name of an extension) that are removed
+	// from the options of all elements.
+	ExcludeOptions []string
+	// LabelNames maps labels (optional, required, repeated) to the text used for LabelDisplay, e.g. "repeated" to
+	// "list". Labels that aren't mapped are displayed as is.
+	LabelNames map[string]string
+	// UsedScalarsOnly limits Template.Scalars to the scalar types that are actually used (see Template.UsedScalars), so
+	// that the built-in templates leave out the table of scalar types when there are none.
+	UsedScalarsOnly bool
+	// DynamicJSONTypes shows the well-known types that hold arbitrary JSON (google.protobuf.Struct, Value, and
+	// ListValue) as "json object", "json value", and "json array" in the LongType of fields.
+	DynamicJSONTypes bool
+	// RootPackage is stripped from the (long) type names of fields, extensions, and methods, so that
+	// mycompany.api.v1.Foo is shown as Foo when it's mycompany.api.v1. Full names and types are left as is. See
+	// Template.RelativeName.
+	RootPackage string
+	// CommentPrefix marks doc comments, e.g. "DOC:". When it's set, the prefix is stripped from comments that start with
+	// it, and all other comments are ignored (i.e. the elements are undocumented).
+	CommentPrefix string
+	// DefaultDescription is used as the description of elements that aren't documented. Either way, the HasDescription
+	// flag of every element tells whether or not it has a description of its own.
+	DefaultDescription string
 }
 
 // NewTemplate creates a Template object from a set of descriptors.
 func NewTemplate(descs []*protokit.FileDescriptor) *Template {
+	return NewTemplateWithOptions(descs, TemplateOptions{})
+}
+
+// NewTemplateWithOptions creates a Template object from a set of descriptors using the supplied options.
+func NewTemplateWithOptions(descs []*protokit.FileDescriptor, opts TemplateOptions) *Template {
 	files := make([]*File, 0, len(descs))
 
 	for _, f := range descs {
-		desc := description(f.GetSyntaxComments().String())
+		desc := description(f.GetSyntaxComments().String(), opts.CommentPrefix)
 		directive := Directive{Descrition: desc}
+		packageDirective := Directive{Descrition: description(f.GetPackageComments().String(), opts.CommentPrefix)}
 		file := &File{
-			Name:          f.GetName(),
-			Exclude:       directive.Exclude(),
-			Package:       f.GetPackage(),
-			HasEnums:      len(f.Enums) > 0,
-			HasExtensions: len(f.Extensions) > 0,
-			HasMessages:   len(f.Messages) > 0,
-			HasServices:   len(f.Services) > 0,
-			Enums:         make(orderedEnums, 0, len(f.Enums)),
-			Extensions:    make(orderedExtensions, 0, len(f.Extensions)),
-			Messages:      make(orderedMessages, 0, len(f.Messages)),
-			Services:      make(orderedServices, 0, len(f.Services)),
-			Options:       mergeOptions(extractOptions(f.GetOptions()), extensions.Transform(f.OptionExtensions)),
-			Description:   description(f.GetSyntaxComments().String()),
+			Name:           f.GetName(),
+			EmbeddedBlocks: directive.Embeds(),
+			Exclude:        directive.Exclude() || packageDirective.Exclude(),
+			Package:        f.GetPackage(),
+			Syntax:         syntaxName(f.GetSyntax()),
+			Edition:        fileEdition(f.FileDescriptorProto),
+			Category:       directive.Category(),
+			Title:          directive.Title(),
+			Version:        directive.Version(),
+			DocsURLs:       append(directive.Docs(), packageDirective.Docs()...),
+			Dependencies:   append([]string{}, f.GetDependency()...),
+			HasEnums:       len(f.Enums) > 0,
+			HasExtensions:  len(f.Extensions) > 0,
+			HasMessages:    len(f.Messages) > 0,
+			HasServices:    len(f.Services) > 0,
+			IsServiceFile:  len(f.Services) > 0,
+			IsTypesOnly:    len(f.Services) == 0 && len(f.Messages)+len(f.Enums) > 0,
+			Enums:          make(orderedEnums, 0, len(f.Enums)),
+			Extensions:     make(orderedExtensions, 0, len(f.Extensions)),
+			Messages:       make(orderedMessages, 0, len(f.Messages)),
+			Services:       make(orderedServices, 0, len(f.Services)),
+			Options:        mergeOptions(extractOptions(f.GetOptions()), extensions.Transform(f.OptionExtensions)),
+			Descriptions:   directive.Translations(),
+			Description:    directive.Descrition,
+			RawComment:     f.GetSyntaxComments().String(),
+			DetachedComments: append(
+				detachedComments(f.GetSyntaxComments(), opts.CommentPrefix),
+				detachedComments(f.GetPackageComments(), opts.CommentPrefix)...,
+			),
+		}
+		if file.Category == "" {
+			file.Category = packageDirective.Category()
+		}
+		if file.Title == "" {
+			file.Title = packageDirective.Title()
+		}
+		if file.Version == "" {
+			file.Version = packageDirective.Version()
+		}
+		if len(file.DocsURLs) > 0 {
+			file.DocsURL = file.DocsURLs[0]
 		}
 
 		for _, e := range f.Enums {
-			file.Enums = append(file.Enums, parseEnum(e))
+			file.Enums = append(file.Enums, parseEnum(e, opts.CommentPrefix))
 		}
 
 		for _, e := range f.Extensions {
-			file.Extensions = append(file.Extensions, parseFileExtension(e))
+			file.Extensions = append(file.Extensions, parseFileExtension(e, opts.CommentPrefix))
 		}
 
+		// protokit doesn't expose the comments of oneof declarations, so they're looked up by their source path.
+		comments := protokit.ParseComments(f.FileDescriptorProto)
+
 		// Recursively add nested types from messages
-		var addFromMessage func(*protokit.Descriptor)
-		addFromMessage = func(m *protokit.Descriptor) {
-			file.Messages = append(file.Messages, parseMessage(m))
+		var addFromMessage func(*protokit.Descriptor, string)
+		addFromMessage = func(m *protokit.Descriptor, path string) {
+			file.Messages = append(file.Messages, parseMessage(m, comments, path, opts.CommentPrefix))
 			for _, e := range m.Enums {
-				file.Enums = append(file.Enums, parseEnum(e))
+				file.Enums = append(file.Enums, parseEnum(e, opts.CommentPrefix))
 			}
-			for _, n := range m.Messages {
-				addFromMessage(n)
+			for i, n := range m.Messages {
+				addFromMessage(n, fmt.Sprintf("%s.%d.%d", path, messageNestedTypePath, i))
 			}
 		}
-		for _, m := range f.Messages {
-			addFromMessage(m)
+		for i, m := range f.Messages {
+			addFromMessage(m, fmt.Sprintf("%d.%d", fileMessageTypePath, i))
 		}
 
 		for _, s := range f.Services {
-			file.Services = append(file.Services, parseService(s))
+			file.Services = append(file.Services, parseService(s, opts.CommentPrefix))
 		}
 
 		sort.Sort(file.Enums)
@@ -88,7 +219,581 @@ func NewTemplate(descs []*protokit.FileDescriptor) *Template {
 		files = append(files, file)
 	}
 
-	return &Template{Files: files, Scalars: scalars}
+	template := &Template{Files: files, Scalars: scalars, RootPackage: opts.RootPackage}
+	idx := newTypeIndex(files)
+	resolveFieldTypes(template, idx, opts)
+	resolveMessageUsage(template, idx)
+	resolveCrossPackage(template, idx)
+	resolveReplacements(template, idx)
+	resolveInlineFields(template, idx, opts.MaxInlineDepth)
+	resolveMapValueFields(template, idx, opts.MaxMapValueDepth)
+	resolveFieldPaths(template, idx, opts.MaxFieldPathDepth)
+	truncateDefaultValues(template, opts.MaxDefaultValueLen)
+	applyLabelNames(template, opts.LabelNames)
+	applyRootPackage(template)
+	excludeOptions(template, opts.ExcludeOptions)
+	storeSortedOptions(template)
+	if opts.NormalizeWhitespace {
+		normalizeDescriptions(template)
+	}
+	if opts.Admonitions {
+		resolveAdmonitions(template)
+	}
+	if opts.EstimateSizes {
+		estimateMessageSizes(template)
+	}
+	if opts.StabilityRisks {
+		resolveStabilityRisks(template)
+	}
+	if opts.UsedScalarsOnly {
+		template.Scalars = template.UsedScalars()
+	}
+	applyDefaultDescriptions(template, opts.DefaultDescription)
+
+	return template
+}
+
+// MarshalCanonical returns a deterministic, indented JSON encoding of the template, suitable for golden files.
+//
+// encoding/json already orders map keys (e.g. options), so the only thing left to normalize is the order of the files
+// which depends on how protoc was invoked. Files are ordered by name, everything else keeps its (semantic) order.
+func (t *Template) MarshalCanonical() ([]byte, error) {
+	files := make([]*File, len(t.Files))
+	copy(files, t.Files)
+	sort.SliceStable(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+
+	canonical := *t
+	canonical.Files = files
+
+	data, err := json.MarshalIndent(&canonical, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(data, '\n'), nil
+}
+
+// UsedScalars returns the entries of Scalars whose proto type is used by at least one field or extension, in the same
+// order as Scalars. When every scalar is used, Scalars itself is returned. When no scalar fields exist, nil is returned.
+func (t *Template) UsedScalars() []*ScalarValue {
+	used := make(map[string]bool)
+	for _, file := range t.Files {
+		for _, ext := range file.Extensions {
+			used[ext.Type] = true
+		}
+
+		for _, msg := range file.Messages {
+			for _, field := range msg.Fields {
+				used[field.Type] = true
+			}
+
+			for _, ext := range msg.Extensions {
+				used[ext.Type] = true
+			}
+		}
+	}
+
+	var result []*ScalarValue
+	for _, scalar := range t.Scalars {
+		if used[scalar.ProtoType] {
+			result = append(result, scalar)
+		}
+	}
+
+	if len(result) == len(t.Scalars) {
+		return t.Scalars
+	}
+
+	return result
+}
+
+// FileGroup is a set of files that share the same `@category` directive.
+type FileGroup struct {
+	Category string  `json:"category"`
+	Files    []*File `json:"files"`
+}
+
+// FilesByCategory groups the files by their category. Groups are sorted by category, with files that don't specify a
+// category collected in a final group with an empty category. Within a group, files stay in the order of Files.
+func (t *Template) FilesByCategory() []*FileGroup {
+	groups := make(map[string]*FileGroup)
+	for _, file := range t.Files {
+		group, ok := groups[file.Category]
+		if !ok {
+			group = &FileGroup{Category: file.Category}
+			groups[file.Category] = group
+		}
+		group.Files = append(group.Files, file)
+	}
+
+	result := make([]*FileGroup, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, group)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if (result[i].Category == "") != (result[j].Category == "") {
+			return result[j].Category == ""
+		}
+		return result[i].Category < result[j].Category
+	})
+
+	return result
+}
+
+// PackageDoc combines the elements of all files that share the same package (see Template.ByPackage).
+type PackageDoc struct {
+	Package    string            `json:"package"`
+	Files      []string          `json:"files"`
+	Enums      orderedEnums      `json:"enums"`
+	Extensions orderedExtensions `json:"extensions"`
+	Messages   orderedMessages   `json:"messages"`
+	Services   orderedServices   `json:"services"`
+}
+
+// ByPackage groups the elements of the files by package, for a package-centric layout of the docs. Packages are
+// sorted by name, and their elements are sorted like the ones in a File. Files lists the names of the files that make
+// up a package, in the order of Template.Files.
+func (t *Template) ByPackage() []PackageDoc {
+	byName := make(map[string]*PackageDoc)
+	for _, file := range t.Files {
+		pkg, ok := byName[file.Package]
+		if !ok {
+			pkg = &PackageDoc{
+				Package:    file.Package,
+				Enums:      make(orderedEnums, 0),
+				Extensions: make(orderedExtensions, 0),
+				Messages:   make(orderedMessages, 0),
+				Services:   make(orderedServices, 0),
+			}
+			byName[file.Package] = pkg
+		}
+
+		pkg.Files = append(pkg.Files, file.Name)
+		pkg.Enums = append(pkg.Enums, file.Enums...)
+		pkg.Extensions = append(pkg.Extensions, file.Extensions...)
+		pkg.Messages = append(pkg.Messages, file.Messages...)
+		pkg.Services = append(pkg.Services, file.Services...)
+	}
+
+	result := make([]PackageDoc, 0, len(byName))
+	for _, pkg := range byName {
+		sort.Sort(pkg.Enums)
+		sort.Sort(pkg.Extensions)
+		sort.Sort(pkg.Messages)
+		sort.Sort(pkg.Services)
+		result = append(result, *pkg)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Package < result[j].Package })
+
+	return result
+}
+
+// truncateDefaultValues shortens the default values of fields and extensions to max characters (plus an ellipsis).
+// The complete values are always available as DefaultValueFull.
+func truncateDefaultValues(t *Template, max int) {
+	truncate := func(full string) string {
+		if runes := []rune(full); max > 0 && len(runes) > max {
+			return string(runes[:max]) + "..."
+		}
+		return full
+	}
+
+	for _, f := range t.Files {
+		for _, ext := range f.Extensions {
+			ext.DefaultValueFull = ext.DefaultValue
+			ext.DefaultValue = truncate(ext.DefaultValue)
+		}
+		for _, m := range f.Messages {
+			for _, field := range m.Fields {
+				field.DefaultValueFull = field.DefaultValue
+				field.DefaultValue = truncate(field.DefaultValue)
+			}
+			for _, ext := range m.Extensions {
+				ext.DefaultValueFull = ext.DefaultValue
+				ext.DefaultValue = truncate(ext.DefaultValue)
+			}
+		}
+	}
+}
+
+// eachDescription calls fn with the description of every element in the template, along with its HasDescription flag.
+func eachDescription(t *Template, fn func(desc *string, hasDesc *bool)) {
+	for _, f := range t.Files {
+		fn(&f.Description, &f.HasDescription)
+		for _, ext := range f.Extensions {
+			fn(&ext.Description, &ext.HasDescription)
+		}
+		for _, m := range f.Messages {
+			fn(&m.Description, &m.HasDescription)
+			for _, field := range m.Fields {
+				fn(&field.Description, &field.HasDescription)
+			}
+			for _, ext := range m.Extensions {
+				fn(&ext.Description, &ext.HasDescription)
+			}
+			for _, oneof := range m.Oneofs {
+				fn(&oneof.Description, &oneof.HasDescription)
+			}
+		}
+		for _, e := range f.Enums {
+			fn(&e.Description, &e.HasDescription)
+			for _, v := range e.Values {
+				fn(&v.Description, &v.HasDescription)
+			}
+		}
+		for _, s := range f.Services {
+			fn(&s.Description, &s.HasDescription)
+			for _, m := range s.Methods {
+				fn(&m.Description, &m.HasDescription)
+			}
+		}
+	}
+}
+
+// applyDefaultDescriptions sets HasDescription on every element that has a (non-blank) description, and replaces the
+// description of the others with defaultDesc, if any.
+func applyDefaultDescriptions(t *Template, defaultDesc string) {
+	eachDescription(t, func(desc *string, hasDesc *bool) {
+		*hasDesc = strings.TrimSpace(*desc) != ""
+		if !*hasDesc && defaultDesc != "" {
+			*desc = defaultDesc
+		}
+	})
+}
+
+// RelativeName returns name (e.g. a FullName or FullType) relative to the root package of the template (see
+// TemplateOptions.RootPackage). Names outside of the root package are returned as is.
+func (t *Template) RelativeName(name string) string {
+	name = strings.TrimPrefix(name, ".")
+	if t.RootPackage == "" {
+		return name
+	}
+	return strings.TrimPrefix(name, t.RootPackage+".")
+}
+
+// applyRootPackage shows the long types of fields, extensions, and methods relative to the root package, if any.
+func applyRootPackage(t *Template) {
+	if t.RootPackage == "" {
+		return
+	}
+
+	relativeExtension := func(ext *FileExtension) {
+		ext.LongType = t.RelativeName(ext.LongType)
+		ext.ContainingLongType = t.RelativeName(ext.ContainingLongType)
+	}
+
+	for _, f := range t.Files {
+		for _, ext := range f.Extensions {
+			relativeExtension(ext)
+		}
+		for _, m := range f.Messages {
+			for _, field := range m.Fields {
+				field.LongType = t.RelativeName(field.LongType)
+				field.MapValueType = t.RelativeName(field.MapValueType)
+			}
+			for _, ext := range m.Extensions {
+				relativeExtension(&ext.FileExtension)
+			}
+		}
+		for _, s := range f.Services {
+			for _, m := range s.Methods {
+				m.RequestLongType = t.RelativeName(m.RequestLongType)
+				m.ResponseLongType = t.RelativeName(m.ResponseLongType)
+			}
+		}
+	}
+}
+
+// applyLabelNames sets the LabelDisplay of fields and extensions, using the configured name of their label if any.
+func applyLabelNames(t *Template, names map[string]string) {
+	display := func(label string) string {
+		if name, ok := names[label]; ok {
+			return name
+		}
+		return label
+	}
+
+	for _, f := range t.Files {
+		for _, ext := range f.Extensions {
+			ext.LabelDisplay = display(ext.Label)
+		}
+		for _, m := range f.Messages {
+			for _, field := range m.Fields {
+				field.LabelDisplay = display(field.Label)
+			}
+			for _, ext := range m.Extensions {
+				ext.LabelDisplay = display(ext.Label)
+			}
+		}
+	}
+}
+
+// typeIndex maps fully qualified type names to the messages and enums that were parsed into a Template.
+type typeIndex struct {
+	messages map[string]*Message
+	enums    map[string]*Enum
+	packages map[string]string
+	files    map[string]string
+}
+
+func newTypeIndex(files []*File) *typeIndex {
+	idx := &typeIndex{
+		messages: make(map[string]*Message),
+		enums:    make(map[string]*Enum),
+		packages: make(map[string]string),
+		files:    make(map[string]string),
+	}
+
+	for _, f := range files {
+		for _, m := range f.Messages {
+			idx.messages[m.FullName] = m
+			idx.packages[m.FullName] = f.Package
+			idx.files[m.FullName] = f.Name
+		}
+		for _, e := range f.Enums {
+			idx.enums[e.FullName] = e
+			idx.packages[e.FullName] = f.Package
+			idx.files[e.FullName] = f.Name
+		}
+	}
+
+	return idx
+}
+
+// anchor returns the anchor of the message or enum with the given full name, or "" when the type is unknown.
+func (idx *typeIndex) anchor(fullName string) string {
+	if m, ok := idx.messages[fullName]; ok {
+		return m.Anchor()
+	}
+	if e, ok := idx.enums[fullName]; ok {
+		return e.Anchor()
+	}
+	return ""
+}
+
+// deprecated returns whether or not the message or enum is marked with the deprecated option.
+func (idx *typeIndex) deprecated(fullName string) bool {
+	var opts map[string]interface{}
+	if m, ok := idx.messages[fullName]; ok {
+		opts = m.Options
+	} else if e, ok := idx.enums[fullName]; ok {
+		opts = e.Options
+	}
+
+	deprecated, _ := opts["deprecated"].(bool)
+	return deprecated
+}
+
+// resolveFieldTypes links message fields to the messages and enums they reference.
+func resolveFieldTypes(t *Template, idx *typeIndex, opts TemplateOptions) {
+	for _, f := range t.Files {
+		for _, m := range f.Messages {
+			for _, field := range m.Fields {
+				if field.TypeKind == typeKindMessage || field.TypeKind == typeKindEnum {
+					field.TypeAnchor = idx.anchor(field.FullType)
+					field.TypeDeprecated = idx.deprecated(field.FullType)
+					field.IsExternalType = idx.files[field.FullType] != f.Name
+				}
+				field.typeMessage = idx.messages[field.FullType]
+				field.typeEnum = idx.enums[field.FullType]
+				if field.typeEnum != nil && field.DefaultValue != "" {
+					field.DefaultEnumAnchor = enumValueAnchor(field.typeEnum, field.DefaultValue)
+				}
+				if e, ok := idx.enums[field.FullType]; ok && opts.InlineEnumValues && field.TypeKind == typeKindEnum {
+					field.EnumValues = e.Values
+				}
+				if field.IsMap {
+					resolveMapField(field, idx)
+				}
+				if field.IsDynamicJSON && opts.DynamicJSONTypes {
+					field.LongType = dynamicJSONTypes[field.FullType]
+				}
+			}
+		}
+	}
+}
+
+// enumValueAnchor returns the anchor of the named value of e, or an empty string if there's no such value.
+func enumValueAnchor(e *Enum, name string) string {
+	for _, v := range e.Values {
+		if v.Name == name {
+			return e.ValueAnchor(name)
+		}
+	}
+	return ""
+}
+
+// resolveMessageUsage flags the messages that are used as the request or response of any service method.
+func resolveMessageUsage(t *Template, idx *typeIndex) {
+	for _, f := range t.Files {
+		for _, s := range f.Services {
+			for _, m := range s.Methods {
+				if msg, ok := idx.messages[m.RequestFullType]; ok {
+					msg.IsRequest = true
+				}
+				if msg, ok := idx.messages[m.ResponseFullType]; ok {
+					msg.IsResponse = true
+				}
+			}
+		}
+	}
+}
+
+// packageOf returns the package of the message or enum with the given full name. For types that aren't part of the
+// Template, the package is derived from the name, assuming the usual convention of lower case packages and capitalized
+// type names (e.g. "google.protobuf" for "google.protobuf.Timestamp").
+func (idx *typeIndex) packageOf(fullName string) string {
+	if pkg, ok := idx.packages[fullName]; ok {
+		return pkg
+	}
+
+	parts := strings.Split(fullName, ".")
+	for i, part := range parts {
+		if part != "" && unicode.IsUpper(rune(part[0])) {
+			return strings.Join(parts[:i], ".")
+		}
+	}
+	return strings.Join(parts[:len(parts)-1], ".")
+}
+
+// resolveCrossPackage sets the packages of the request and response types of all service methods, and flags the ones
+// whose request or response type is defined in another package than the service itself.
+func resolveCrossPackage(t *Template, idx *typeIndex) {
+	for _, f := range t.Files {
+		for _, s := range f.Services {
+			for _, m := range s.Methods {
+				m.RequestPackage = idx.packageOf(m.RequestFullType)
+				m.ResponsePackage = idx.packageOf(m.ResponseFullType)
+				m.CrossPackage = m.RequestPackage != f.Package || m.ResponsePackage != f.Package
+			}
+		}
+	}
+}
+
+// resolveReplacements sets the ReplacedByAnchor of deprecated elements whose replacement (see Directive.ReplacedBy) is a
+// message or enum of the Template. The replacement is looked up by full name, and then relative to the file's package.
+func resolveReplacements(t *Template, idx *typeIndex) {
+	resolve := func(f *File, replacedBy string, anchor *string) {
+		if replacedBy == "" {
+			return
+		}
+		name := strings.TrimPrefix(replacedBy, ".")
+		if *anchor = idx.anchor(name); *anchor == "" && f.Package != "" {
+			*anchor = idx.anchor(f.Package + "." + name)
+		}
+	}
+
+	for _, f := range t.Files {
+		for _, m := range f.Messages {
+			resolve(f, m.ReplacedBy, &m.ReplacedByAnchor)
+			for _, field := range m.Fields {
+				resolve(f, field.ReplacedBy, &field.ReplacedByAnchor)
+			}
+		}
+		for _, e := range f.Enums {
+			resolve(f, e.ReplacedBy, &e.ReplacedByAnchor)
+			for _, v := range e.Values {
+				resolve(f, v.ReplacedBy, &v.ReplacedByAnchor)
+			}
+		}
+		for _, s := range f.Services {
+			resolve(f, s.ReplacedBy, &s.ReplacedByAnchor)
+			for _, m := range s.Methods {
+				resolve(f, m.ReplacedBy, &m.ReplacedByAnchor)
+			}
+		}
+	}
+}
+
+// resolveInlineFields expands the fields of the request and response messages of all methods, up to maxDepth levels.
+func resolveInlineFields(t *Template, idx *typeIndex, maxDepth int) {
+	if maxDepth < 1 {
+		maxDepth = 1
+	}
+
+	for _, f := range t.Files {
+		for _, s := range f.Services {
+			for _, m := range s.Methods {
+				if msg, ok := idx.messages[m.RequestFullType]; ok {
+					m.RequestFields = inlineFields(msg, idx, 1, maxDepth)
+				}
+				if msg, ok := idx.messages[m.ResponseFullType]; ok {
+					m.ResponseFields = inlineFields(msg, idx, 1, maxDepth)
+				}
+			}
+		}
+	}
+}
+
+// resolveMapValueFields expands the fields of the message values of all map fields, up to maxDepth levels. Nothing is
+// expanded when maxDepth is below 1.
+func resolveMapValueFields(t *Template, idx *typeIndex, maxDepth int) {
+	if maxDepth < 1 {
+		return
+	}
+
+	for _, f := range t.Files {
+		for _, m := range f.Messages {
+			for _, field := range m.Fields {
+				if !field.IsMap || !field.MapValueIsMessage {
+					continue
+				}
+				if value, ok := idx.messages[mapValueFullType(field, idx)]; ok {
+					field.MapValueFields = inlineFields(value, idx, 1, maxDepth)
+				}
+			}
+		}
+	}
+}
+
+// mapValueFullType returns the full type of the values of a map field, or "" when its map entry message is unknown.
+func mapValueFullType(field *MessageField, idx *typeIndex) string {
+	if entry, ok := idx.messages[field.FullType]; ok {
+		for _, f := range entry.Fields {
+			if f.Name == "value" {
+				return f.FullType
+			}
+		}
+	}
+	return ""
+}
+
+func inlineFields(msg *Message, idx *typeIndex, depth, maxDepth int) []*InlineField {
+	fields := make([]*InlineField, 0, len(msg.Fields))
+	for _, field := range msg.Fields {
+		inline := &InlineField{MessageField: field, Depth: depth}
+		if child, ok := idx.messages[field.FullType]; ok && field.TypeKind == typeKindMessage {
+			if depth < maxDepth {
+				inline.Fields = inlineFields(child, idx, depth+1, maxDepth)
+			} else {
+				inline.IsLink = true
+			}
+		}
+		fields = append(fields, inline)
+	}
+
+	return fields
+}
+
+// resolveMapField fills in the key and value details of a map field from its (synthetic) map entry message.
+func resolveMapField(field *MessageField, idx *typeIndex) {
+	entry, ok := idx.messages[field.FullType]
+	if !ok {
+		return
+	}
+
+	for _, f := range entry.Fields {
+		switch f.Name {
+		case "key":
+			field.MapKeyType = f.LongType
+		case "value":
+			field.MapValueType = f.LongType
+			field.MapValueIsMessage = f.TypeKind == typeKindMessage
+			if f.TypeKind == typeKindMessage || f.TypeKind == typeKindEnum {
+				field.MapValueAnchor = idx.anchor(f.FullType)
+			}
+		}
+	}
 }
 
 func makeScalars() []*ScalarValue {
@@ -148,69 +853,225 @@ func extractOptions(opts commonOptions) map[string]interface{} {
 // fields, service methods) will be in the order that they're defined within their respective proto files.
 //
 // In the case of proto3 files, HasExtensions will always be false, and Extensions will be empty.
+//
+// A file is marked with Exclude when its syntax or package comment contains `@exclude`. Likewise, `@category <name>`
+// in either comment sets Category, which is used to group files into documentation sections, and `@title` and
+// `@version` set Title and Version (e.g. the API version of everything in the file).
+//
+// Dependencies lists the names of the files imported by this file, in the order of the import statements.
+//
+// DetachedComments holds the comments separated from the syntax and package statements by blank lines (e.g. license
+// headers or section dividers). Messages, fields, enums, services, and the like have them as well.
+//
+// Syntax is one of "proto2", "proto3", or "editions". For the latter, Edition holds the edition (e.g. "2023") and field
+// labels reflect the field_presence feature ("optional" for explicit presence, "" for implicit presence).
+//
+// IsServiceFile is set when the file defines any services (e.g. for API style docs), IsTypesOnly when it defines
+// messages or enums but no services (e.g. for schema style docs). Files with neither are neither.
 type File struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Package     string `json:"package"`
+	Name             string   `json:"name"`
+	Description      string   `json:"description"`
+	HasDescription   bool     `json:"hasDescription"`
+	Package          string   `json:"package"`
+	Syntax           string   `json:"syntax"`
+	Edition          string   `json:"edition"`
+	Category         string   `json:"category"`
+	Title            string   `json:"title"`
+	Version          string   `json:"version"`
+	RawComment       string   `json:"rawComment"`
+	DetachedComments []string `json:"detachedComments"`
+
+	Dependencies []string `json:"dependencies"`
 
 	HasEnums      bool `json:"hasEnums"`
 	HasExtensions bool `json:"hasExtensions"`
 	HasMessages   bool `json:"hasMessages"`
 	HasServices   bool `json:"hasServices"`
+	IsServiceFile bool `json:"isServiceFile"`
+	IsTypesOnly   bool `json:"isTypesOnly"`
 	Exclude       bool `json:"exclude"`
 
+	DocsURL  string   `json:"docsURL"`
+	DocsURLs []string `json:"docsURLs"`
+
 	Enums      orderedEnums      `json:"enums"`
 	Extensions orderedExtensions `json:"extensions"`
 	Messages   orderedMessages   `json:"messages"`
 	Services   orderedServices   `json:"services"`
 
-	Options map[string]interface{} `json:"options,omitempty"`
+	Descriptions  map[string]string      `json:"descriptions"`
+	Options       map[string]interface{} `json:"-"`
+	SortedOptions []SortedOption         `json:"options,omitempty"`
+
+	EmbeddedBlocks []string `json:"embeddedBlocks"`
+
+	admonitions []Admonition
 }
 
 // Option returns the named option.
 func (f File) Option(name string) interface{} { return f.Options[name] }
 
+// OrderedMessages returns the messages in the order given by their `@order` directives. Messages without one follow
+// those with one, sorted by LongName. Messages itself is left untouched.
+func (f File) OrderedMessages() []*Message {
+	ordered := append([]*Message(nil), f.Messages...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return orderedBefore(ordered[i].Order, ordered[j].Order, ordered[i].LongName, ordered[j].LongName)
+	})
+	return ordered
+}
+
+// OrderedServices returns the services in the order given by their `@order` directives (see OrderedMessages).
+func (f File) OrderedServices() []*Service {
+	ordered := append([]*Service(nil), f.Services...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return orderedBefore(ordered[i].Order, ordered[j].Order, ordered[i].LongName, ordered[j].LongName)
+	})
+	return ordered
+}
+
+// OrderedEnums returns the enums in the order given by their `@order` directives (see OrderedMessages).
+func (f File) OrderedEnums() []*Enum {
+	ordered := append([]*Enum(nil), f.Enums...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return orderedBefore(ordered[i].Order, ordered[j].Order, ordered[i].LongName, ordered[j].LongName)
+	})
+	return ordered
+}
+
+// orderedBefore reports whether an element with order a and name nameA is listed before one with order b and name
+// nameB. An order of 0 means the element doesn't have one.
+func orderedBefore(a, b int, nameA, nameB string) bool {
+	switch {
+	case a != 0 && b != 0 && a != b:
+		return a < b
+	case a != 0 && b == 0:
+		return true
+	case a == 0 && b != 0:
+		return false
+	}
+	return nameA < nameB
+}
+
 // FileExtension contains details about top-level extensions within a proto(2) file.
+//
+// DefaultValue may be truncated (see TemplateOptions.MaxDefaultValueLen), DefaultValueFull is always complete.
+//
+// LabelDisplay is the text to show for Label (see TemplateOptions.LabelNames).
 type FileExtension struct {
-	Name               string `json:"name"`
-	LongName           string `json:"longName"`
-	FullName           string `json:"fullName"`
-	Description        string `json:"description"`
-	Label              string `json:"label"`
-	Type               string `json:"type"`
-	LongType           string `json:"longType"`
-	FullType           string `json:"fullType"`
-	Number             int    `json:"number"`
-	DefaultValue       string `json:"defaultValue"`
-	ContainingType     string `json:"containingType"`
-	ContainingLongType string `json:"containingLongType"`
-	ContainingFullType string `json:"containingFullType"`
+	Name               string   `json:"name"`
+	LongName           string   `json:"longName"`
+	FullName           string   `json:"fullName"`
+	Description        string   `json:"description"`
+	HasDescription     bool     `json:"hasDescription"`
+	RawComment         string   `json:"rawComment"`
+	DetachedComments   []string `json:"detachedComments"`
+	Label              string   `json:"label"`
+	LabelDisplay       string   `json:"labelDisplay"`
+	Type               string   `json:"type"`
+	LongType           string   `json:"longType"`
+	FullType           string   `json:"fullType"`
+	Number             int      `json:"number"`
+	DefaultValue       string   `json:"defaultValue"`
+	DefaultValueFull   string   `json:"defaultValueFull"`
+	ContainingType     string   `json:"containingType"`
+	ContainingLongType string   `json:"containingLongType"`
+	ContainingFullType string   `json:"containingFullType"`
 }
 
 // Message contains details about a protobuf message.
 //
 // In the case of proto3 files, HasExtensions will always be false, and Extensions will be empty.
+//
+// IsRequest and IsResponse are set when the message is the request or response type of any service method in the
+// Template.
+//
+// EstimatedMinSize is a rough lower bound (in bytes) of the encoded message, based on its required scalar fields. It's
+// only computed when TemplateOptions.EstimateSizes is set. MaxSize is the size limit enforced by the server (e.g.
+// "1MB"), as documented with the `@max_size` directive.
+//
+// IsSingleFieldWrapper is set when the message merely wraps a single field (e.g. `repeated Foo items = 1;` of a list
+// response), that isn't part of a oneof. See WrappedField.
+//
+// ReplacedBy names the element to use instead of a message marked with `@deprecated -> NewMessage`. ReplacedByAnchor is
+// the anchor of that element when it's a message or enum of the Template. The same goes for the other elements.
+//
+// Descriptions holds the translations of Description set with `@lang:<code>` directives, keyed by language code (see
+// Directive.Translations). Files, fields, enums, services, and the like have them as well.
 type Message struct {
-	Name        string `json:"name"`
-	LongName    string `json:"longName"`
-	FullName    string `json:"fullName"`
-	Description string `json:"description"`
+	Name             string   `json:"name"`
+	LongName         string   `json:"longName"`
+	FullName         string   `json:"fullName"`
+	Description      string   `json:"description"`
+	HasDescription   bool     `json:"hasDescription"`
+	RawComment       string   `json:"rawComment"`
+	DetachedComments []string `json:"detachedComments"`
 
 	HasExtensions bool `json:"hasExtensions"`
 	HasFields     bool `json:"hasFields"`
 	HasOneofs     bool `json:"hasOneofs"`
 
+	IsSingleFieldWrapper bool `json:"isSingleFieldWrapper"`
+
+	IsRequest  bool `json:"isRequest"`
+	IsResponse bool `json:"isResponse"`
+
+	EstimatedMinSize int    `json:"estimatedMinSize"`
+	MaxSize          string `json:"maxSize"`
+
+	Stability string `json:"stability"`
+	Order     int    `json:"order"`
+
 	Extensions []*MessageExtension `json:"extensions"`
 	Fields     []*MessageField     `json:"fields"`
+	Oneofs     []*Oneof            `json:"oneofs"`
 
 	Exclude bool `json:"exclude"`
 
-	Options map[string]interface{} `json:"options,omitempty"`
+	DocsURL          string   `json:"docsURL"`
+	DocsURLs         []string `json:"docsURLs"`
+	ReplacedBy       string   `json:"replacedBy"`
+	ReplacedByAnchor string   `json:"replacedByAnchor"`
+	Since            string   `json:"since"`
+
+	Descriptions  map[string]string      `json:"descriptions"`
+	Options       map[string]interface{} `json:"-"`
+	SortedOptions []SortedOption         `json:"options,omitempty"`
+
+	EmbeddedBlocks []string `json:"embeddedBlocks"`
+
+	fieldPaths  []string
+	admonitions []Admonition
 }
 
 // Option returns the named option.
 func (m Message) Option(name string) interface{} { return m.Options[name] }
 
+// IdentifierField returns the first field marked with the `@id` directive, or nil when there's none.
+func (m Message) IdentifierField() *MessageField {
+	for _, f := range m.Fields {
+		if f.IsIdentifier {
+			return f
+		}
+	}
+	return nil
+}
+
+// WrappedField returns the only field of a single field wrapper (see IsSingleFieldWrapper), or nil for other messages.
+func (m Message) WrappedField() *MessageField {
+	if !m.IsSingleFieldWrapper {
+		return nil
+	}
+	return m.Fields[0]
+}
+
+// Anchor returns the identifier used to link to this message in the generated docs.
+func (m Message) Anchor() string { return m.FullName }
+
+// NameParts returns the components of the (package relative) long name of this message, i.e. the names of the messages
+// it's nested in followed by its own name. For example, ["Outer", "Inner", "Leaf"] for Outer.Inner.Leaf.
+func (m Message) NameParts() []string { return nameParts(m.LongName, m.Name) }
+
 // FieldOptions returns all options that are set on the fields in this message.
 func (m Message) FieldOptions() []string {
 	optionSet := make(map[string]struct{})
@@ -222,122 +1083,608 @@ func (m Message) FieldOptions() []string {
 	if len(optionSet) == 0 {
 		return nil
 	}
-	options := make([]string, 0, len(optionSet))
-	for option := range optionSet {
-		options = append(options, option)
+	options := make([]string, 0, len(optionSet))
+	for option := range optionSet {
+		options = append(options, option)
+	}
+	sort.Strings(options)
+	return options
+}
+
+// FieldsWithOption returns all fields that have the given option set.
+// If no single value has the option set, this returns nil.
+func (m Message) FieldsWithOption(optionName string) []*MessageField {
+	fields := make([]*MessageField, 0, len(m.Fields))
+	for _, field := range m.Fields {
+		if _, ok := field.Options[optionName]; ok {
+			fields = append(fields, field)
+		}
+	}
+	if len(fields) > 0 {
+		return fields
+	}
+	return nil
+}
+
+// Oneof contains details about a oneof declaration within a message. The synthetic oneofs generated for proto3
+// optional fields aren't included.
+//
+// The member fields are already part of Message.Fields, so the JSON output only references them by name (FieldNames).
+//
+// Exhaustive is set by the `@exhaustive` directive, which documents that exactly one of the members must be set.
+type Oneof struct {
+	Name           string          `json:"name"`
+	Description    string          `json:"description"`
+	HasDescription bool            `json:"hasDescription"`
+	RawComment     string          `json:"rawComment"`
+	Exhaustive     bool            `json:"exhaustive"`
+	FieldNames     []string        `json:"fields"`
+	Fields         []*MessageField `json:"-"`
+}
+
+type Directive struct {
+	Descrition  string
+	action      string
+	version     string
+	since       string
+	title       string
+	displayType string
+	deadline    string
+	maxSize     string
+	status      int
+	category    string
+	stability   string
+	order       int
+	docs        []string
+	deprecated  bool
+	replacedBy  string
+	ranges      []ValueRange
+	translated  map[string]string
+	params      map[string]string
+	embeds      []string
+}
+
+func (d *Directive) Exclude() bool {
+	exclude := strings.Contains(d.Descrition, "@exclude")
+	if exclude {
+		d.Descrition = strings.ReplaceAll(d.Descrition, "@exclude", "")
+	}
+	return exclude
+}
+
+// Flags returns whether or not the `@flags` directive is present, i.e. the values of an enum are bit flags.
+func (d *Directive) Flags() bool {
+	flags := flagsRegex.MatchString(d.Descrition)
+	if flags {
+		d.Descrition = flagsRegex.ReplaceAllString(d.Descrition, "")
+	}
+	return flags
+}
+
+// Hex returns whether or not the `@hex` directive is present, i.e. the values of an enum are shown in hexadecimal.
+// Directives that merely start with "@hex" (e.g. `@hexdump`) don't count.
+func (d *Directive) Hex() bool {
+	hex := hexRegex.MatchString(d.Descrition)
+	if hex {
+		d.Descrition = hexRegex.ReplaceAllString(d.Descrition, "")
+	}
+	return hex
+}
+
+// ReadOnly returns whether or not the `@readonly` directive is present, i.e. the field is populated by the server.
+func (d *Directive) ReadOnly() bool {
+	readOnly := readOnlyRegex.MatchString(d.Descrition)
+	if readOnly {
+		d.Descrition = readOnlyRegex.ReplaceAllString(d.Descrition, "")
+	}
+	return readOnly
+}
+
+// WriteOnly returns whether or not the `@writeonly` directive is present, i.e. the field is only sent by clients.
+func (d *Directive) WriteOnly() bool {
+	writeOnly := writeOnlyRegex.MatchString(d.Descrition)
+	if writeOnly {
+		d.Descrition = writeOnlyRegex.ReplaceAllString(d.Descrition, "")
+	}
+	return writeOnly
+}
+
+// ID returns whether or not the `@id` directive is present, i.e. the field identifies the resource (its primary key).
+// Directives that merely start with "@id" (e.g. `@idempotent`) don't count.
+func (d *Directive) ID() bool {
+	id := idRegex.MatchString(d.Descrition)
+	if id {
+		d.Descrition = idRegex.ReplaceAllString(d.Descrition, "")
+	}
+	return id
+}
+
+// Exhaustive returns whether or not the `@exhaustive` directive is present, i.e. one member of a oneof must be set.
+func (d *Directive) Exhaustive() bool {
+	exhaustive := exhaustiveRegex.MatchString(d.Descrition)
+	if exhaustive {
+		d.Descrition = exhaustiveRegex.ReplaceAllString(d.Descrition, "")
+	}
+	return exhaustive
+}
+
+// NoSchema returns whether or not the `@no_schema` directive is present, i.e. a field is left out of generated
+// examples.
+func (d *Directive) NoSchema() bool {
+	noSchema := noSchemaRegex.MatchString(d.Descrition)
+	if noSchema {
+		d.Descrition = noSchemaRegex.ReplaceAllString(d.Descrition, "")
+	}
+	return noSchema
+}
+
+func (d *Directive) Required() bool {
+	required := strings.Contains(d.Descrition, "@required")
+	if required {
+		d.Descrition = strings.ReplaceAll(d.Descrition, "@required", "")
+	}
+	return required
+}
+
+// Title returns the value of the `@title` directive. Like all directives with a value, every occurrence is stripped
+// from the description and the first one wins (e.g. when comments from multiple sources were concatenated).
+func (d *Directive) Title() string {
+	if d.title != "" {
+		return d.title
+	}
+	titles := titleRegex.FindAllString(d.Descrition, -1)
+	title := ""
+	if len(titles) > 0 {
+		title = strings.ReplaceAll(titles[0], "@title", "")
+		d.Descrition = titleRegex.ReplaceAllString(d.Descrition, "")
+	}
+	d.title = strings.TrimSpace(title)
+
+	return d.title
+}
+
+func (d *Directive) Action() string {
+	if d.action != "" {
+		return d.action
+	}
+	actions := actionRegex.FindAllString(d.Descrition, -1)
+	action := ""
+	if len(actions) > 0 {
+		action = strings.ReplaceAll(actions[0], "@action", "")
+		d.Descrition = actionRegex.ReplaceAllString(d.Descrition, "")
+	}
+	d.action = strings.TrimSpace(action)
+
+	return d.action
+}
+
+// Type returns the value of the `@type <type>` directive, the type to display in place of a field's real type (e.g.
+// "JWT" for a bytes field). The directive must start a line or follow whitespace, so that JSON examples (e.g. the
+// "@type" key of google.protobuf.Any values) aren't mistaken for it. All occurrences are removed from the description.
+func (d *Directive) Type() string {
+	if d.displayType != "" {
+		return d.displayType
+	}
+	displayType := ""
+	if match := typeRegex.FindStringSubmatch(d.Descrition); match != nil {
+		displayType = match[2]
+		d.Descrition = typeRegex.ReplaceAllString(d.Descrition, "$1")
+	}
+	d.displayType = strings.TrimSpace(displayType)
+
+	return d.displayType
+}
+
+// Deadline returns the value of the `@deadline` directive. Values that aren't valid durations (e.g. "30s" or "1m30s")
+// are dropped.
+func (d *Directive) Deadline() string {
+	if d.deadline != "" {
+		return d.deadline
+	}
+	deadline := ""
+	if match := deadlineRegex.FindStringSubmatch(d.Descrition); match != nil {
+		deadline = match[1]
+		d.Descrition = deadlineRegex.ReplaceAllString(d.Descrition, "")
+	}
+	if _, err := time.ParseDuration(deadline); err != nil {
+		deadline = ""
+	}
+	d.deadline = deadline
+
+	return d.deadline
+}
+
+// MaxSize returns the value of the `@max_size` directive, a size limit such as "1MB", "512 KiB", or "4096". Values that
+// don't look like sizes are dropped.
+func (d *Directive) MaxSize() string {
+	if d.maxSize != "" {
+		return d.maxSize
+	}
+	size := ""
+	if match := maxSizeRegex.FindStringSubmatch(d.Descrition); match != nil {
+		size = strings.TrimSpace(match[1])
+		d.Descrition = maxSizeRegex.ReplaceAllString(d.Descrition, "")
+	}
+	if !sizeRegex.MatchString(size) {
+		size = ""
+	}
+	d.maxSize = size
+
+	return d.maxSize
+}
+
+// Status returns the value of the `@status` directive, the HTTP status code of a successful response (e.g. 201). Values
+// that aren't valid status codes are dropped, in which case 0 is returned.
+func (d *Directive) Status() int {
+	if d.status != 0 {
+		return d.status
+	}
+	status := 0
+	if match := statusRegex.FindStringSubmatch(d.Descrition); match != nil {
+		status, _ = strconv.Atoi(match[1])
+		d.Descrition = statusRegex.ReplaceAllString(d.Descrition, "")
+	}
+	if status < 100 || status > 599 {
+		status = 0
+	}
+	d.status = status
+
+	return d.status
+}
+
+// Retryable returns whether or not the `@retryable` directive is present, i.e. a failed call can safely be retried.
+// Directives that merely start with "@retryable" (e.g. `@retryable_errors`) don't count.
+func (d *Directive) Retryable() bool {
+	retryable := retryableRegex.MatchString(d.Descrition)
+	if retryable {
+		d.Descrition = retryableRegex.ReplaceAllString(d.Descrition, "")
+	}
+	return retryable
+}
+
+func (d *Directive) Version() string {
+	if d.version != "" {
+		return d.version
+	}
+	versions := versionRegex.FindAllString(d.Descrition, -1)
+	version := ""
+	if len(versions) > 0 {
+		version = strings.ReplaceAll(versions[0], "@version", "")
+		d.Descrition = versionRegex.ReplaceAllString(d.Descrition, "")
+	}
+	d.version = strings.TrimSpace(version)
+
+	return d.version
+}
+
+// Since returns the value of the `@since` directive, the version an element was added in (see Template.Changelog).
+func (d *Directive) Since() string {
+	if d.since != "" {
+		return d.since
+	}
+	if match := sinceRegex.FindStringSubmatch(d.Descrition); match != nil {
+		d.since = match[1]
+		d.Descrition = sinceRegex.ReplaceAllString(d.Descrition, "")
+	}
+
+	return d.since
+}
+
+// Stability returns the maturity of an element, as set by the `@alpha`, `@beta`, or `@stable` directives. When more
+// than one of them is present, the last one wins (so a later `@stable` overrides an earlier `@beta`). All of them are
+// stripped from the description.
+func (d *Directive) Stability() string {
+	if d.stability != "" {
+		return d.stability
+	}
+	matches := stabilityRegex.FindAllStringSubmatch(d.Descrition, -1)
+	if len(matches) > 0 {
+		d.stability = matches[len(matches)-1][1]
+		d.Descrition = stabilityRegex.ReplaceAllString(d.Descrition, "")
+	}
+
+	return d.stability
+}
+
+// Category returns the value of the `@category` directive, the documentation section a file is grouped into (see
+// Template.FilesByCategory). The first occurrence wins and every occurrence is stripped from the description.
+func (d *Directive) Category() string {
+	if d.category != "" {
+		return d.category
+	}
+	if match := categoryRegex.FindStringSubmatch(d.Descrition); match != nil {
+		d.category = strings.TrimSpace(match[1])
+		d.Descrition = categoryRegex.ReplaceAllString(d.Descrition, "")
+	}
+
+	return d.category
+}
+
+// Order returns the value of the `@order` directive, the position of an element when it's listed with hand-curated
+// ordering (see File.OrderedMessages). Values that aren't integers are dropped, in which case 0 is returned.
+func (d *Directive) Order() int {
+	if d.order != 0 {
+		return d.order
+	}
+	if match := orderRegex.FindStringSubmatch(d.Descrition); match != nil {
+		d.order, _ = strconv.Atoi(match[1])
+		d.Descrition = orderRegex.ReplaceAllString(d.Descrition, "")
+	}
+
+	return d.order
+}
+
+// Docs returns the URLs of all `@docs <url>` directives, which link to external documentation of an element (e.g. a
+// hand-written guide). The result is empty, not nil, when there are none.
+func (d *Directive) Docs() []string {
+	if d.docs != nil {
+		return d.docs
+	}
+	d.docs = make([]string, 0)
+	for _, match := range docsRegex.FindAllStringSubmatch(d.Descrition, -1) {
+		d.docs = append(d.docs, match[1])
 	}
-	sort.Strings(options)
-	return options
+	d.Descrition = docsRegex.ReplaceAllString(d.Descrition, "")
+
+	return d.docs
 }
 
-// FieldsWithOption returns all fields that have the given option set.
-// If no single value has the option set, this returns nil.
-func (m Message) FieldsWithOption(optionName string) []*MessageField {
-	fields := make([]*MessageField, 0, len(m.Fields))
-	for _, field := range m.Fields {
-		if _, ok := field.Options[optionName]; ok {
-			fields = append(fields, field)
-		}
+// DocsURL returns the URL of the first `@docs <url>` directive (see Docs), or "" when there's none.
+func (d *Directive) DocsURL() string {
+	if docs := d.Docs(); len(docs) > 0 {
+		return docs[0]
 	}
-	if len(fields) > 0 {
-		return fields
+	return ""
+}
+
+// Deprecated returns whether or not the `@deprecated` directive is present. It can name the element to use instead
+// (e.g. `@deprecated -> NewMessage`), see ReplacedBy.
+func (d *Directive) Deprecated() bool {
+	if d.deprecated {
+		return true
 	}
-	return nil
+	matches := deprecRegex.FindAllStringSubmatch(d.Descrition, -1)
+	if len(matches) > 0 {
+		d.deprecated = true
+		d.replacedBy = matches[0][1]
+		d.Descrition = deprecRegex.ReplaceAllString(d.Descrition, "")
+	}
+
+	return d.deprecated
 }
 
-type Directive struct {
-	Descrition string
-	action     string
-	version    string
-	title      string
+// ReplacedBy returns the target of the `@deprecated -> <name>` directive, or "" when the element is not deprecated
+// or no replacement was named.
+func (d *Directive) ReplacedBy() string {
+	d.Deprecated()
+	return d.replacedBy
 }
 
-func (d *Directive) Exclude() bool {
-	exclude := strings.Contains(d.Descrition, "@exclude")
-	if exclude {
-		d.Descrition = strings.ReplaceAll(d.Descrition, "@exclude", "")
+// deprecatedOption returns the options of an element marked with the `@deprecated` directive, so it's treated as if it
+// had the deprecated option set.
+func deprecatedOption(d *Directive) map[string]interface{} {
+	if !d.Deprecated() {
+		return nil
 	}
-	return exclude
+	return map[string]interface{}{"deprecated": true}
 }
 
-func (d *Directive) Required() bool {
-	required := strings.Contains(d.Descrition, "@required")
-	if required {
-		d.Descrition = strings.ReplaceAll(d.Descrition, "@required", "")
+// Ranges returns the value ranges of all `@range <start>-<end> <label>` directives, e.g. `@range 1000-1999 Client
+// Errors`. The result is empty, not nil, when there are none.
+func (d *Directive) Ranges() []ValueRange {
+	if d.ranges != nil {
+		return d.ranges
 	}
-	return required
+	d.ranges = make([]ValueRange, 0)
+	for _, match := range rangeRegex.FindAllStringSubmatch(d.Descrition, -1) {
+		start, _ := strconv.Atoi(match[1])
+		end, _ := strconv.Atoi(match[2])
+		d.ranges = append(d.ranges, ValueRange{Start: start, End: end, Label: strings.TrimSpace(match[3])})
+	}
+	d.Descrition = rangeRegex.ReplaceAllString(d.Descrition, "")
+
+	return d.ranges
 }
 
-func (d *Directive) Title() string {
-	if d.title != "" {
-		return d.title
+// Embeds returns the blocks enclosed in `@embed:begin` and `@embed:end` directives (e.g. raw HTML or a diagram), in
+// order, and removes them from the description. The blocks are captured verbatim, apart from the surrounding line
+// breaks, so this should run before any other directive. The result is empty, not nil, when there are none.
+func (d *Directive) Embeds() []string {
+	if d.embeds != nil {
+		return d.embeds
 	}
-	titles := titleRegex.FindAllString(d.Descrition, -1)
-	title := ""
-	if len(titles) > 0 {
-		title = strings.ReplaceAll(titles[0], "@title", "")
-		d.Descrition = strings.ReplaceAll(d.Descrition, titles[0], "")
+	d.embeds = make([]string, 0)
+	for _, match := range embedRegex.FindAllStringSubmatch(d.Descrition, -1) {
+		d.embeds = append(d.embeds, strings.Trim(match[1], "\n"))
+	}
+	if len(d.embeds) > 0 {
+		d.Descrition = strings.TrimSpace(embedRegex.ReplaceAllString(d.Descrition, ""))
 	}
-	d.title = strings.TrimSpace(title)
 
-	return d.title
+	return d.embeds
 }
 
-func (d *Directive) Action() string {
-	if d.action != "" {
-		return d.action
+// Params returns the descriptions of request fields set with `@param <field> <description>` directives (as in javadoc),
+// keyed by field name. Each description runs until the end of its line, and the first one wins when a field is
+// documented more than once. The result is empty, not nil, when there are none.
+func (d *Directive) Params() map[string]string {
+	if d.params != nil {
+		return d.params
 	}
-	actions := actionRegex.FindAllString(d.Descrition, -1)
-	action := ""
-	if len(actions) > 0 {
-		action = strings.ReplaceAll(actions[0], "@action", "")
-		d.Descrition = strings.ReplaceAll(d.Descrition, actions[0], "")
 
+	d.params = make(map[string]string)
+	for _, match := range paramRegex.FindAllStringSubmatch(d.Descrition, -1) {
+		if _, ok := d.params[match[1]]; !ok {
+			d.params[match[1]] = strings.TrimSpace(match[2])
+		}
 	}
-	d.action = strings.TrimSpace(action)
+	d.Descrition = paramRegex.ReplaceAllString(d.Descrition, "")
 
-	return d.action
+	return d.params
 }
 
-func (d *Directive) Version() string {
-	if d.version != "" {
-		return d.version
+// Translations returns the translated descriptions introduced by `@lang:<code>` directives, keyed by language code,
+// e.g. "es" for `@lang:es Una cosa.`. A translation runs until the next `@lang:` directive or the end of the
+// description. All of them are removed from the description, which keeps the untagged text as the default. The result
+// is nil when there are no translations.
+//
+// Other directives are stripped from the translations only when they're processed before this one.
+func (d *Directive) Translations() map[string]string {
+	if d.translated != nil {
+		return d.translated
 	}
-	versions := versionRegex.FindAllString(d.Descrition, -1)
-	version := ""
-	if len(versions) > 0 {
-		version = strings.ReplaceAll(versions[0], "@version", "")
-		d.Descrition = strings.ReplaceAll(d.Descrition, versions[0], "")
+
+	tags := langRegex.FindAllStringSubmatchIndex(d.Descrition, -1)
+	if len(tags) == 0 {
+		return nil
 	}
-	d.version = strings.TrimSpace(version)
 
-	return d.version
+	d.translated = make(map[string]string, len(tags))
+	for i, tag := range tags {
+		end := len(d.Descrition)
+		if i+1 < len(tags) {
+			end = tags[i+1][0]
+		}
+		lang := d.Descrition[tag[2]:tag[3]]
+		if _, ok := d.translated[lang]; !ok {
+			d.translated[lang] = strings.TrimSpace(d.Descrition[tag[1]:end])
+		}
+	}
+	d.Descrition = strings.TrimSpace(d.Descrition[:tags[0][0]])
+
+	return d.translated
 }
 
+// Kinds of types a MessageField can have (see MessageField.TypeKind).
+const (
+	typeKindScalar  = "scalar"
+	typeKindEnum    = "enum"
+	typeKindMessage = "message"
+	typeKindMap     = "map"
+)
+
 // MessageField contains details about an individual field within a message.
 //
 // In the case of proto3 files, DefaultValue will always be empty. Similarly, label will be empty unless the field is
-// repeated (in which case it'll be "repeated").
+// repeated (in which case it'll be "repeated") or declared `optional` (in which case it'll be "optional"). The
+// synthetic oneofs of such optional fields don't make them oneof members (IsOneof). DefaultValue may be truncated (see
+// TemplateOptions.MaxDefaultValueLen), DefaultValueFull is always complete. When the field is an enum of the Template,
+// DefaultEnumAnchor links to the default value (see Enum.ValueAnchor).
+//
+// OneofIndex is the index of the field's oneof declaration within the message (nil if it has none), which includes
+// the synthetic oneofs. Those are flagged with IsSyntheticOneof.
+//
+// TypeKind is one of "scalar", "enum", "message", or "map". TypeAnchor holds the anchor of the referenced message or
+// enum, and is empty for scalars, maps, and types that aren't part of the Template. TypeDeprecated is set when that
+// message or enum is deprecated (regardless of whether or not the field itself is). IsExternalType is set when that
+// message or enum is defined in another file (including files that aren't part of the Template), i.e. links to it
+// leave the docs of the current file.
+//
+// For map fields, MapKeyType and MapValueType hold the (long) types of the map's keys and values. When the values are
+// messages or enums, MapValueAnchor links to them and MapValueIsMessage tells which of the two it is. The fields of
+// message values are expanded in MapValueFields, up to TemplateOptions.MaxMapValueDepth levels (by default they're
+// not). It's nil for maps of scalars and enums.
+//
+// Packed is set for repeated scalar (numeric, bool, or enum) fields that use the packed encoding, which is the default
+// in proto3 files and can be changed with the `packed` option.
+//
+// EnumValues lists the values of the referenced enum. It's only populated when TemplateOptions.InlineEnumValues is set.
+//
+// DisplayType is set by the `@type` directive and is meant to be shown in place of LongType (e.g. for bytes fields
+// that hold a specific encoding). The real type information is left untouched. Likewise, LabelDisplay is meant to be
+// shown in place of Label (see TemplateOptions.LabelNames).
+//
+// ReadOnly marks fields that are populated by the server, and WriteOnly fields that are only sent by clients. They're
+// set by the `@readonly` and `@writeonly` directives, or the OUTPUT_ONLY and INPUT_ONLY values of the
+// google.api.field_behavior option respectively.
+//
+// IsIdentifier is set by the `@id` directive and marks the field that identifies the resource described by the message
+// (see Message.IdentifierField).
+//
+// IsDynamicJSON is set for fields of the well-known types that hold arbitrary JSON (google.protobuf.Struct, Value, and
+// ListValue). See TemplateOptions.DynamicJSONTypes.
+//
+// HasCustomJSONName is set when the json_name option overrides the default JSON name (the lowerCamelCase field name),
+// i.e. the field is encoded under a different name than clients would expect.
+//
+// NoSchema is set by the `@no_schema` directive. Such fields are still documented, but left out of generated examples
+// (e.g. ServiceMethod.CurlExample).
+//
+// StabilityRisk is one of the StabilityRisk* values, rating how risky it is to change the field based on whether it's
+// required, part of a request message, or has a low number. It's only set when TemplateOptions.StabilityRisks is.
 type MessageField struct {
-	Name         string `json:"name"`
-	Description  string `json:"description"`
-	Label        string `json:"label"`
-	Type         string `json:"type"`
-	LongType     string `json:"longType"`
-	FullType     string `json:"fullType"`
-	IsMap        bool   `json:"ismap"`
-	IsOneof      bool   `json:"isoneof"`
-	OneofDecl    string `json:"oneofdecl"`
-	DefaultValue string `json:"defaultValue"`
-	Required     bool   `json:"required"`
-	IsPrimitive  bool   `json:"isprimitive"`
-
-	Options map[string]interface{} `json:"options,omitempty"`
+	Name              string   `json:"name"`
+	JSONName          string   `json:"jsonName"`
+	HasCustomJSONName bool     `json:"hasCustomJSONName"`
+	Number            int      `json:"number"`
+	Description       string   `json:"description"`
+	HasDescription    bool     `json:"hasDescription"`
+	RawComment        string   `json:"rawComment"`
+	DetachedComments  []string `json:"detachedComments"`
+	Label             string   `json:"label"`
+	LabelDisplay      string   `json:"labelDisplay"`
+	Type              string   `json:"type"`
+	LongType          string   `json:"longType"`
+	FullType          string   `json:"fullType"`
+	TypeKind          string   `json:"typeKind"`
+	TypeAnchor        string   `json:"typeAnchor"`
+	TypeDeprecated    bool     `json:"typeDeprecated"`
+	DisplayType       string   `json:"displayType"`
+	IsMap             bool     `json:"ismap"`
+	MapKeyType        string   `json:"mapKeyType"`
+	MapValueType      string   `json:"mapValueType"`
+	MapValueIsMessage bool     `json:"mapValueIsMessage"`
+	MapValueAnchor    string   `json:"mapValueAnchor"`
+	IsOneof           bool     `json:"isoneof"`
+	OneofDecl         string   `json:"oneofdecl"`
+	OneofIndex        *int     `json:"oneofIndex"`
+	IsSyntheticOneof  bool     `json:"isSyntheticOneof"`
+	DefaultValue      string   `json:"defaultValue"`
+	DefaultValueFull  string   `json:"defaultValueFull"`
+	DefaultEnumAnchor string   `json:"defaultEnumAnchor"`
+	Required          bool     `json:"required"`
+	ReadOnly          bool     `json:"readOnly"`
+	WriteOnly         bool     `json:"writeOnly"`
+	IsIdentifier      bool     `json:"isIdentifier"`
+	NoSchema          bool     `json:"noSchema"`
+	IsDynamicJSON     bool     `json:"isDynamicJSON"`
+	IsExternalType    bool     `json:"isExternalType"`
+	DocsURL           string   `json:"docsURL"`
+	DocsURLs          []string `json:"docsURLs"`
+	ReplacedBy        string   `json:"replacedBy"`
+	ReplacedByAnchor  string   `json:"replacedByAnchor"`
+	Since             string   `json:"since"`
+	IsPrimitive       bool     `json:"isprimitive"`
+	Packed            bool     `json:"packed"`
+	StabilityRisk     string   `json:"stabilityRisk"`
+
+	EnumValues []*EnumValue `json:"enumValues,omitempty"`
+
+	MapValueFields []*InlineField `json:"-"`
+
+	Descriptions  map[string]string      `json:"descriptions"`
+	Options       map[string]interface{} `json:"-"`
+	SortedOptions []SortedOption         `json:"options,omitempty"`
+
+	EmbeddedBlocks []string `json:"embeddedBlocks"`
+
+	admonitions []Admonition
+	typeMessage *Message // the referenced message (or map entry), when it's part of the Template
+	typeEnum    *Enum    // the referenced enum, when it's part of the Template
 }
 
 // Option returns the named option.
 func (f MessageField) Option(name string) interface{} { return f.Options[name] }
 
+// MapTypeString returns the type of a map field the way it's declared, e.g. "map<string, Vehicle>". For other fields,
+// and maps whose key and value types aren't known, it returns LongType.
+func (f MessageField) MapTypeString() string {
+	if !f.IsMap || f.MapKeyType == "" || f.MapValueType == "" {
+		return f.LongType
+	}
+	return fmt.Sprintf("map<%s, %s>", f.MapKeyType, f.MapValueType)
+}
+
 // MessageExtension contains details about message-scoped extensions in proto(2) files.
 type MessageExtension struct {
 	FileExtension
@@ -348,20 +1695,122 @@ type MessageExtension struct {
 }
 
 // Enum contains details about enumerations. These can be either top level enums, or nested (defined within a message).
+//
+// ZeroValue points at the value numbered 0, which is the default value of the enum. It's nil when there isn't one,
+// which is only valid in proto2 files.
+//
+// IsFlags is set by the `@flags` directive, for enums whose values are bit flags (see EnumValue.BitPosition).
+//
+// ValueRanges groups the values of large enums into logical sections, as set by `@range <start>-<end> <label>`
+// directives (e.g. `@range 1000-1999 Client Errors`). See ValuesInRange.
 type Enum struct {
-	Name        string       `json:"name"`
-	LongName    string       `json:"longName"`
-	FullName    string       `json:"fullName"`
-	Description string       `json:"description"`
-	Values      []*EnumValue `json:"values"`
-	Exclude     bool         `json:"exclude"`
-
-	Options map[string]interface{} `json:"options,omitempty"`
+	Name             string       `json:"name"`
+	LongName         string       `json:"longName"`
+	FullName         string       `json:"fullName"`
+	Description      string       `json:"description"`
+	HasDescription   bool         `json:"hasDescription"`
+	RawComment       string       `json:"rawComment"`
+	DetachedComments []string     `json:"detachedComments"`
+	Values           []*EnumValue `json:"values"`
+	ZeroValue        *EnumValue   `json:"-"`
+	Exclude          bool         `json:"exclude"`
+	DocsURL          string       `json:"docsURL"`
+	DocsURLs         []string     `json:"docsURLs"`
+	ReplacedBy       string       `json:"replacedBy"`
+	ReplacedByAnchor string       `json:"replacedByAnchor"`
+	Since            string       `json:"since"`
+	Hex              bool         `json:"hex"`
+	IsFlags          bool         `json:"isFlags"`
+	ValueRanges      []ValueRange `json:"valueRanges"`
+	Order            int          `json:"order"`
+
+	Descriptions  map[string]string      `json:"descriptions"`
+	Options       map[string]interface{} `json:"-"`
+	SortedOptions []SortedOption         `json:"options,omitempty"`
+
+	EmbeddedBlocks []string `json:"embeddedBlocks"`
+
+	admonitions []Admonition
 }
 
 // Option returns the named option.
 func (e Enum) Option(name string) interface{} { return e.Options[name] }
 
+// Anchor returns the identifier used to link to this enum in the generated docs.
+func (e Enum) Anchor() string { return e.FullName }
+
+// ValueAnchor returns the identifier used to link to the named value of this enum, e.g. "com.example.Color.RED".
+func (e Enum) ValueAnchor(name string) string { return e.Anchor() + "." + name }
+
+// ValuesInRange returns the values of this enum whose numbers are within the range, in the order they're defined.
+func (e Enum) ValuesInRange(r ValueRange) []*EnumValue {
+	values := make([]*EnumValue, 0)
+	for _, v := range e.Values {
+		if number, err := strconv.Atoi(v.Number); err == nil && r.Contains(number) {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// maxNumberGaps limits how many missing numbers Enum.NumberGaps reports, e.g. for enums with a value near the int32
+// limits.
+const maxNumberGaps = 1000
+
+// NumberGaps returns the numbers between the lowest and the highest value of this enum that aren't used by any value,
+// in ascending order. Aliases (see the allow_alias option) count as one value. It's meant to spot accidental gaps in
+// enums that should be contiguous, and returns at most 1000 numbers. The result is empty, not nil, when there are none.
+func (e Enum) NumberGaps() []int {
+	gaps := make([]int, 0)
+	used := make(map[int]bool, len(e.Values))
+	for _, v := range e.Values {
+		if number, err := strconv.Atoi(v.Number); err == nil {
+			used[number] = true
+		}
+	}
+	if len(used) == 0 {
+		return gaps
+	}
+
+	lowest, highest := math.MaxInt64, math.MinInt64
+	for number := range used {
+		if number < lowest {
+			lowest = number
+		}
+		if number > highest {
+			highest = number
+		}
+	}
+
+	for number := lowest + 1; number < highest && len(gaps) < maxNumberGaps; number++ {
+		if !used[number] {
+			gaps = append(gaps, number)
+		}
+	}
+	return gaps
+}
+
+// ValueRange is a labeled range of enum values, both ends included (see Enum.ValueRanges).
+type ValueRange struct {
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+	Label string `json:"label"`
+}
+
+// Contains returns whether or not number is within the range.
+func (r ValueRange) Contains(number int) bool { return number >= r.Start && number <= r.End }
+
+// NameParts returns the components of the (package relative) long name of this enum, see Message.NameParts.
+func (e Enum) NameParts() []string { return nameParts(e.LongName, e.Name) }
+
+// nameParts splits a long name into its nesting components, falling back to name when there's no long name.
+func nameParts(longName, name string) []string {
+	if longName == "" {
+		return []string{name}
+	}
+	return strings.Split(longName, ".")
+}
+
 // ValueOptions returns all options that are set on the values in this enum.
 func (e Enum) ValueOptions() []string {
 	optionSet := make(map[string]struct{})
@@ -397,33 +1846,86 @@ func (e Enum) ValuesWithOption(optionName string) []*EnumValue {
 }
 
 // EnumValue contains details about an individual value within an enumeration.
+//
+// BitPosition is only set for the values of flags enums (see Enum.IsFlags). It's the position of the bit represented
+// by the value (e.g. 3 for 8), or -1 when the value isn't a power of two (e.g. NONE = 0 or combinations of flags).
 type EnumValue struct {
-	Name        string `json:"name"`
-	Number      string `json:"number"`
-	Description string `json:"description"`
-
-	Options map[string]interface{} `json:"options,omitempty"`
+	Name             string   `json:"name"`
+	Number           string   `json:"number"`
+	NumberHex        string   `json:"numberHex"`
+	BitPosition      int      `json:"bitPosition"`
+	DocsURL          string   `json:"docsURL"`
+	DocsURLs         []string `json:"docsURLs"`
+	ReplacedBy       string   `json:"replacedBy"`
+	ReplacedByAnchor string   `json:"replacedByAnchor"`
+	Since            string   `json:"since"`
+	Description      string   `json:"description"`
+	HasDescription   bool     `json:"hasDescription"`
+	RawComment       string   `json:"rawComment"`
+	DetachedComments []string `json:"detachedComments"`
+
+	Descriptions  map[string]string      `json:"descriptions"`
+	Options       map[string]interface{} `json:"-"`
+	SortedOptions []SortedOption         `json:"options,omitempty"`
+
+	EmbeddedBlocks []string `json:"embeddedBlocks"`
+
+	admonitions []Admonition
 }
 
 // Option returns the named option.
 func (v EnumValue) Option(name string) interface{} { return v.Options[name] }
 
 // Service contains details about a service definition within a proto file.
+//
+// Stability is one of "alpha", "beta", or "stable" when set with the corresponding directive, messages and methods
+// support it as well.
 type Service struct {
-	Name        string           `json:"name"`
-	LongName    string           `json:"longName"`
-	FullName    string           `json:"fullName"`
-	Description string           `json:"description"`
-	Methods     []*ServiceMethod `json:"methods"`
-	Title       string           `json:"title"`
-	Exclude     bool             `json:"exclude"`
-
-	Options map[string]interface{} `json:"options,omitempty"`
+	Name             string           `json:"name"`
+	LongName         string           `json:"longName"`
+	FullName         string           `json:"fullName"`
+	Description      string           `json:"description"`
+	HasDescription   bool             `json:"hasDescription"`
+	RawComment       string           `json:"rawComment"`
+	DetachedComments []string         `json:"detachedComments"`
+	Methods          []*ServiceMethod `json:"methods"`
+	Title            string           `json:"title"`
+	Exclude          bool             `json:"exclude"`
+	DocsURL          string           `json:"docsURL"`
+	DocsURLs         []string         `json:"docsURLs"`
+	ReplacedBy       string           `json:"replacedBy"`
+	ReplacedByAnchor string           `json:"replacedByAnchor"`
+	Since            string           `json:"since"`
+	Stability        string           `json:"stability"`
+	Order            int              `json:"order"`
+
+	Descriptions  map[string]string      `json:"descriptions"`
+	Options       map[string]interface{} `json:"-"`
+	SortedOptions []SortedOption         `json:"options,omitempty"`
+
+	EmbeddedBlocks []string `json:"embeddedBlocks"`
+
+	admonitions []Admonition
 }
 
 // Option returns the named option.
 func (s Service) Option(name string) interface{} { return s.Options[name] }
 
+// Anchor returns the identifier used to link to this service (and its methods) in the generated docs.
+func (s Service) Anchor() string { return s.FullName }
+
+// VisibleMethodCount returns the number of methods in this service that aren't excluded from the docs (see
+// Directive.Exclude), e.g. for headings like "Billing (5 methods)".
+func (s Service) VisibleMethodCount() int {
+	count := 0
+	for _, method := range s.Methods {
+		if !method.Exclude {
+			count++
+		}
+	}
+	return count
+}
+
 // MethodOptions returns all options that are set on the methods in this service.
 func (s Service) MethodOptions() []string {
 	optionSet := make(map[string]struct{})
@@ -458,28 +1960,175 @@ func (s Service) MethodsWithOption(optionName string) []*ServiceMethod {
 	return nil
 }
 
+// MethodGroup is a set of service methods that share the same `@action` directive.
+type MethodGroup struct {
+	Action  string           `json:"action"`
+	Methods []*ServiceMethod `json:"methods"`
+}
+
+// MethodsByAction groups the methods in this service by their action. Groups are sorted by action, with methods that
+// don't specify an action collected in a final group with an empty action. Within a group, methods stay in the order
+// they're defined in.
+func (s Service) MethodsByAction() []*MethodGroup {
+	groups := make(map[string]*MethodGroup)
+	for _, method := range s.Methods {
+		group, ok := groups[method.Action]
+		if !ok {
+			group = &MethodGroup{Action: method.Action}
+			groups[method.Action] = group
+		}
+		group.Methods = append(group.Methods, method)
+	}
+
+	result := make([]*MethodGroup, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, group)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if (result[i].Action == "") != (result[j].Action == "") {
+			return result[j].Action == ""
+		}
+		return result[i].Action < result[j].Action
+	})
+
+	return result
+}
+
+// Values of ServiceMethod.IdempotencyLevel (see the idempotency_level method option).
+const (
+	IdempotencyUnknown       = "IDEMPOTENCY_UNKNOWN"
+	IdempotencyNoSideEffects = "NO_SIDE_EFFECTS"
+	IdempotencyIdempotent    = "IDEMPOTENT"
+)
+
+// Values of ServiceMethod.StreamingKind.
+const (
+	StreamingKindUnary  = "unary"
+	StreamingKindClient = "client_streaming"
+	StreamingKindServer = "server_streaming"
+	StreamingKindBidi   = "bidi_streaming"
+)
+
+// Values of ServiceMethod.RequestWellKnownKind and ResponseWellKnownKind.
+const (
+	WellKnownEmpty  = "empty"
+	WellKnownAny    = "any"
+	WellKnownStruct = "struct"
+)
+
+// wellKnownKinds maps the well-known types that methods commonly take or return to their WellKnown* kind.
+var wellKnownKinds = map[string]string{
+	"google.protobuf.Empty":  WellKnownEmpty,
+	"google.protobuf.Any":    WellKnownAny,
+	"google.protobuf.Struct": WellKnownStruct,
+}
+
 // ServiceMethod contains details about an individual method within a service.
+//
+// FullMethodPath is the path the method is invoked with on the wire, e.g. "/com.example.VehicleService/GetVehicle".
+//
+// Deadline and Retryable are operational hints for clients, set with the `@deadline <duration>` and `@retryable`
+// directives.
+//
+// RequestFields and ResponseFields are the fields of the request and response messages, with message typed fields
+// expanded up to TemplateOptions.MaxInlineDepth levels. They're left out of the JSON output, which already contains the
+// messages themselves.
+//
+// RequestStreamNote and ResponseStreamNote spell out the streaming semantics, e.g. "stream of Vehicle" when any number
+// of Vehicle messages is sent in that direction. They're empty for unary requests and responses.
+//
+// StreamingKind sums up RequestStreaming and ResponseStreaming as one of the StreamingKind* values.
+//
+// IdempotencyLevel is the value of the idempotency_level option, IdempotencyUnknown when it isn't set. Options also
+// contains it (as "idempotency_level") when it's set explicitly. Idempotent is set for IdempotencyIdempotent and
+// IdempotencyNoSideEffects, Safe only for the latter.
+//
+// SuccessStatus is the HTTP status code of a successful response, set with the `@status <code>` directive. It is 0 when
+// not specified, in which case 200 is implied.
+//
+// CrossPackage is set when the request or response type is defined in another package than the service, i.e. the
+// method is part of a contract between packages. RequestPackage and ResponsePackage hold the packages of the request
+// and response types (e.g. for a package badge next to them), and are empty for types without a package.
+//
+// InferredVerb is derived from the conventional prefix of the method name (e.g. "list" for ListBooks), and is one of
+// "get", "list", "create", "update", "delete", or "batch". It's empty when the name doesn't follow the convention. Unlike
+// Action, it doesn't require any directive.
+//
+// ParamDocs documents the fields of the request in the method comment, as set with `@param <field> <description>`
+// directives. Templates can use them for request fields without comments of their own.
+//
+// RequestIsWellKnown is set when the request is google.protobuf.Empty, Any, or Struct, which docs may want to show
+// specially rather than link to. RequestWellKnownKind is then one of the WellKnown* values. Likewise for responses.
 type ServiceMethod struct {
-	Name              string                 `json:"name"`
-	Description       string                 `json:"description"`
-	RequestType       string                 `json:"requestType"`
-	RequestLongType   string                 `json:"requestLongType"`
-	RequestFullType   string                 `json:"requestFullType"`
-	RequestStreaming  bool                   `json:"requestStreaming"`
-	ResponseType      string                 `json:"responseType"`
-	ResponseLongType  string                 `json:"responseLongType"`
-	ResponseFullType  string                 `json:"responseFullType"`
-	ResponseStreaming bool                   `json:"responseStreaming"`
-	Title             string                 `json:"title"`
-	Action            string                 `json:"action"`
-	Version           string                 `json:"version"`
-	Exclude           bool                   `json:"exclude"`
-	Options           map[string]interface{} `json:"options,omitempty"`
+	Name               string                 `json:"name"`
+	FullMethodPath     string                 `json:"fullMethodPath"`
+	Description        string                 `json:"description"`
+	HasDescription     bool                   `json:"hasDescription"`
+	RawComment         string                 `json:"rawComment"`
+	DetachedComments   []string               `json:"detachedComments"`
+	RequestType        string                 `json:"requestType"`
+	RequestLongType    string                 `json:"requestLongType"`
+	RequestFullType    string                 `json:"requestFullType"`
+	RequestPackage     string                 `json:"requestPackage"`
+	RequestStreaming   bool                   `json:"requestStreaming"`
+	ResponseType       string                 `json:"responseType"`
+	ResponseLongType   string                 `json:"responseLongType"`
+	ResponseFullType   string                 `json:"responseFullType"`
+	ResponsePackage    string                 `json:"responsePackage"`
+	ResponseStreaming  bool                   `json:"responseStreaming"`
+	StreamingKind      string                 `json:"streamingKind"`
+	CrossPackage       bool                   `json:"crossPackage"`
+	RequestStreamNote  string                 `json:"requestStreamNote"`
+	ResponseStreamNote string                 `json:"responseStreamNote"`
+	IdempotencyLevel   string                 `json:"idempotencyLevel"`
+	Idempotent         bool                   `json:"idempotent"`
+	Safe               bool                   `json:"safe"`
+	RequestFields      []*InlineField         `json:"-"`
+	ResponseFields     []*InlineField         `json:"-"`
+	Title              string                 `json:"title"`
+	Action             string                 `json:"action"`
+	InferredVerb       string                 `json:"inferredVerb"`
+	Version            string                 `json:"version"`
+	Deadline           string                 `json:"deadline"`
+	Retryable          bool                   `json:"retryable"`
+	SuccessStatus      int                    `json:"successStatus"`
+	Stability          string                 `json:"stability"`
+	Exclude            bool                   `json:"exclude"`
+	DocsURL            string                 `json:"docsURL"`
+	DocsURLs           []string               `json:"docsURLs"`
+	ReplacedBy         string                 `json:"replacedBy"`
+	ReplacedByAnchor   string                 `json:"replacedByAnchor"`
+	Since              string                 `json:"since"`
+	ParamDocs          map[string]string      `json:"paramDocs"`
+	Descriptions       map[string]string      `json:"descriptions"`
+	EmbeddedBlocks     []string               `json:"embeddedBlocks"`
+	Options            map[string]interface{} `json:"-"`
+	SortedOptions      []SortedOption         `json:"options,omitempty"`
+
+	RequestIsWellKnown    bool   `json:"requestIsWellKnown"`
+	RequestWellKnownKind  string `json:"requestWellKnownKind"`
+	ResponseIsWellKnown   bool   `json:"responseIsWellKnown"`
+	ResponseWellKnownKind string `json:"responseWellKnownKind"`
+
+	admonitions []Admonition
 }
 
 // Option returns the named option.
 func (m ServiceMethod) Option(name string) interface{} { return m.Options[name] }
 
+// InlineField is a field of a request or response message as shown inline with a method. Depth is 1 for the fields of
+// the message itself, 2 for the fields of those fields, and so on.
+//
+// Message typed fields list their own fields in Fields. Once the maximum depth is reached, they're marked with IsLink
+// instead, meaning they should be rendered as a link to the message (see TypeAnchor).
+type InlineField struct {
+	*MessageField
+
+	Depth  int            `json:"depth"`
+	IsLink bool           `json:"isLink"`
+	Fields []*InlineField `json:"fields"`
+}
+
 // ScalarValue contains information about scalar value types in protobuf. The common use case for this type is to know
 // which language specific type maps to the protobuf type.
 //
@@ -497,39 +2146,87 @@ type ScalarValue struct {
 	RubyType   string `json:"rubyType"`
 }
 
-func parseEnum(pe *protokit.EnumDescriptor) *Enum {
-	desc := description(pe.GetComments().String())
+func parseEnum(pe *protokit.EnumDescriptor, commentPrefix string) *Enum {
+	desc := description(pe.GetComments().String(), commentPrefix)
 	directive := &Directive{Descrition: desc}
 
 	enum := &Enum{
-		Name:        pe.GetName(),
-		LongName:    pe.GetLongName(),
-		FullName:    pe.GetFullName(),
-		Exclude:     directive.Exclude(),
-		Description: directive.Descrition,
-		Options:     mergeOptions(extractOptions(pe.GetOptions()), extensions.Transform(pe.OptionExtensions)),
+		Name:             pe.GetName(),
+		LongName:         pe.GetLongName(),
+		FullName:         pe.GetFullName(),
+		EmbeddedBlocks:   directive.Embeds(),
+		Exclude:          directive.Exclude(),
+		Hex:              directive.Hex(),
+		IsFlags:          directive.Flags(),
+		ValueRanges:      directive.Ranges(),
+		DocsURL:          directive.DocsURL(),
+		DocsURLs:         directive.Docs(),
+		ReplacedBy:       directive.ReplacedBy(),
+		Since:            directive.Since(),
+		Order:            directive.Order(),
+		Descriptions:     directive.Translations(),
+		Description:      directive.Descrition,
+		RawComment:       pe.GetComments().String(),
+		DetachedComments: detachedComments(pe.GetComments(), commentPrefix),
+		Options: mergeOptions(
+			extractOptions(pe.GetOptions()),
+			extensions.Transform(pe.OptionExtensions),
+			deprecatedOption(directive),
+		),
 	}
 
 	for _, val := range pe.GetValues() {
+		number := fmt.Sprint(val.GetNumber())
+		valueDirective := &Directive{Descrition: description(val.GetComments().String(), commentPrefix)}
 		enum.Values = append(enum.Values, &EnumValue{
-			Name:        val.GetName(),
-			Number:      fmt.Sprint(val.GetNumber()),
-			Description: description(val.GetComments().String()),
-			Options:     mergeOptions(extractOptions(val.GetOptions()), extensions.Transform(val.OptionExtensions)),
+			Name:             val.GetName(),
+			Number:           number,
+			NumberHex:        hexNumber(number),
+			EmbeddedBlocks:   valueDirective.Embeds(),
+			DocsURL:          valueDirective.DocsURL(),
+			DocsURLs:         valueDirective.Docs(),
+			ReplacedBy:       valueDirective.ReplacedBy(),
+			Since:            valueDirective.Since(),
+			Descriptions:     valueDirective.Translations(),
+			Description:      valueDirective.Descrition,
+			RawComment:       val.GetComments().String(),
+			DetachedComments: detachedComments(val.GetComments(), commentPrefix),
+			Options: mergeOptions(
+				extractOptions(val.GetOptions()),
+				extensions.Transform(val.OptionExtensions),
+				deprecatedOption(valueDirective),
+			),
 		})
+
+		if val.GetNumber() == 0 && enum.ZeroValue == nil {
+			enum.ZeroValue = enum.Values[len(enum.Values)-1]
+		}
+		if enum.IsFlags {
+			enum.Values[len(enum.Values)-1].BitPosition = bitPosition(val.GetNumber())
+		}
 	}
 
 	return enum
 }
 
-func parseFileExtension(pe *protokit.ExtensionDescriptor) *FileExtension {
+// bitPosition returns the position of the only bit set in n (e.g. 3 for 8), or -1 when n isn't a power of two.
+func bitPosition(n int32) int {
+	if n <= 0 || n&(n-1) != 0 {
+		return -1
+	}
+	return bits.TrailingZeros32(uint32(n))
+}
+
+func parseFileExtension(pe *protokit.ExtensionDescriptor, commentPrefix string) *FileExtension {
 	t, lt, ft := parseType(pe)
 
 	return &FileExtension{
 		Name:               pe.GetName(),
 		LongName:           pe.GetLongName(),
 		FullName:           pe.GetFullName(),
-		Description:        description(pe.GetComments().String()),
+		Description:        description(pe.GetComments().String(), commentPrefix),
+		RawComment:         pe.GetComments().String(),
+		DetachedComments:   detachedComments(pe.GetComments(), commentPrefix),
 		Label:              labelName(pe.GetLabel(), pe.IsProto3(), pe.GetProto3Optional()),
 		Type:               t,
 		LongType:           lt,
@@ -537,53 +2234,108 @@ func parseFileExtension(pe *protokit.ExtensionDescriptor) *FileExtension {
 		Number:             int(pe.GetNumber()),
 		DefaultValue:       pe.GetDefaultValue(),
 		ContainingType:     baseName(pe.GetExtendee()),
-		ContainingLongType: strings.TrimPrefix(pe.GetExtendee(), "."+pe.GetPackage()+"."),
-		ContainingFullType: strings.TrimPrefix(pe.GetExtendee(), "."),
+		ContainingLongType: shortenType(pe.GetExtendee(), pe.GetPackage()),
+		ContainingFullType: shortenType(pe.GetExtendee(), ""),
 	}
 }
 
-func parseMessage(pm *protokit.Descriptor) *Message {
-	desc := description(pm.GetComments().String())
+func parseMessage(pm *protokit.Descriptor, comments protokit.Comments, path, commentPrefix string) *Message {
+	desc := description(pm.GetComments().String(), commentPrefix)
 
 	directive := &Directive{Descrition: desc}
 	msg := &Message{
-		Name:          pm.GetName(),
-		LongName:      pm.GetLongName(),
-		FullName:      pm.GetFullName(),
-		Exclude:       directive.Exclude(),
-		Description:   directive.Descrition,
-		HasExtensions: len(pm.GetExtensions()) > 0,
-		HasFields:     len(pm.GetMessageFields()) > 0,
-		HasOneofs:     len(pm.GetOneofDecl()) > 0,
-		Extensions:    make([]*MessageExtension, 0, len(pm.Extensions)),
-		Fields:        make([]*MessageField, 0, len(pm.Fields)),
-		Options:       mergeOptions(extractOptions(pm.GetOptions()), extensions.Transform(pm.OptionExtensions)),
+		Name:             pm.GetName(),
+		LongName:         pm.GetLongName(),
+		FullName:         pm.GetFullName(),
+		EmbeddedBlocks:   directive.Embeds(),
+		Exclude:          directive.Exclude(),
+		Stability:        directive.Stability(),
+		Order:            directive.Order(),
+		DocsURL:          directive.DocsURL(),
+		DocsURLs:         directive.Docs(),
+		ReplacedBy:       directive.ReplacedBy(),
+		MaxSize:          directive.MaxSize(),
+		Since:            directive.Since(),
+		Descriptions:     directive.Translations(),
+		Description:      directive.Descrition,
+		RawComment:       pm.GetComments().String(),
+		DetachedComments: detachedComments(pm.GetComments(), commentPrefix),
+		HasExtensions:    len(pm.GetExtensions()) > 0,
+		HasFields:        len(pm.GetMessageFields()) > 0,
+		Extensions:       make([]*MessageExtension, 0, len(pm.Extensions)),
+		Fields:           make([]*MessageField, 0, len(pm.Fields)),
+		Options: mergeOptions(
+			extractOptions(pm.GetOptions()),
+			extensions.Transform(pm.OptionExtensions),
+			deprecatedOption(directive),
+		),
 	}
 
 	for _, ext := range pm.Extensions {
-		msg.Extensions = append(msg.Extensions, parseMessageExtension(ext))
+		msg.Extensions = append(msg.Extensions, parseMessageExtension(ext, commentPrefix))
 	}
 
 	for _, f := range pm.Fields {
-		msg.Fields = append(msg.Fields, parseMessageField(f, pm.GetOneofDecl()))
+		msg.Fields = append(msg.Fields, parseMessageField(f, pm.GetOneofDecl(), commentPrefix))
 	}
 
+	msg.Oneofs = parseOneofs(pm, comments, path, msg.Fields, commentPrefix)
+	msg.HasOneofs = len(msg.Oneofs) > 0
+	msg.IsSingleFieldWrapper = len(msg.Fields) == 1 && !msg.Fields[0].IsOneof
+
 	return msg
 }
 
-func parseMessageExtension(pe *protokit.ExtensionDescriptor) *MessageExtension {
+func parseOneofs(
+	pm *protokit.Descriptor, comments protokit.Comments, path string, fields []*MessageField, commentPrefix string,
+) []*Oneof {
+	oneofs := make([]*Oneof, 0, len(pm.GetOneofDecl()))
+	byIndex := make(map[int32]*Oneof)
+
+	for i, decl := range pm.GetOneofDecl() {
+		comment := comments.Get(fmt.Sprintf("%s.%d.%d", path, messageOneofDeclPath, i))
+		directive := Directive{Descrition: description(comment.String(), commentPrefix)}
+		byIndex[int32(i)] = &Oneof{
+			Name:        decl.GetName(),
+			Exhaustive:  directive.Exhaustive(),
+			Description: directive.Descrition,
+			RawComment:  comment.String(),
+		}
+	}
+
+	for i, pf := range pm.Fields {
+		if pf.OneofIndex == nil || pf.GetProto3Optional() {
+			continue
+		}
+		oneof := byIndex[pf.GetOneofIndex()]
+		oneof.Fields = append(oneof.Fields, fields[i])
+		oneof.FieldNames = append(oneof.FieldNames, fields[i].Name)
+	}
+
+	for i := range pm.GetOneofDecl() {
+		if oneof := byIndex[int32(i)]; len(oneof.Fields) > 0 {
+			oneofs = append(oneofs, oneof)
+		}
+	}
+
+	return oneofs
+}
+
+func parseMessageExtension(pe *protokit.ExtensionDescriptor, commentPrefix string) *MessageExtension {
 	return &MessageExtension{
-		FileExtension: *parseFileExtension(pe),
+		FileExtension: *parseFileExtension(pe, commentPrefix),
 		ScopeType:     pe.GetParent().GetName(),
 		ScopeLongType: pe.GetParent().GetLongName(),
 		ScopeFullType: pe.GetParent().GetFullName(),
 	}
 }
 
-func parseMessageField(pf *protokit.FieldDescriptor, oneofDecls []*descriptor.OneofDescriptorProto) *MessageField {
+func parseMessageField(
+	pf *protokit.FieldDescriptor, oneofDecls []*descriptor.OneofDescriptorProto, commentPrefix string,
+) *MessageField {
 	t, lt, ft := parseType(pf)
 
-	desc := description(pf.GetComments().String())
+	desc := description(pf.GetComments().String(), commentPrefix)
 
 	directive := Directive{Descrition: desc}
 
@@ -594,81 +2346,268 @@ func parseMessageField(pf *protokit.FieldDescriptor, oneofDecls []*descriptor.On
 			break
 		}
 	}
+	behaviors := fieldBehaviors(pf)
 	m := &MessageField{
-		Name:         pf.GetName(),
-		Label:        labelName(pf.GetLabel(), pf.IsProto3(), pf.GetProto3Optional()),
-		Type:         t,
-		LongType:     lt,
-		FullType:     ft,
-		DefaultValue: pf.GetDefaultValue(),
-		Options:      mergeOptions(extractOptions(pf.GetOptions()), extensions.Transform(pf.OptionExtensions)),
-		IsOneof:      pf.OneofIndex != nil,
-		Required:     directive.Required(),
-		Description:  directive.Descrition,
-		IsPrimitive:  isPrimitive,
+		Name:             pf.GetName(),
+		JSONName:         jsonName(pf.FieldDescriptorProto),
+		Number:           int(pf.GetNumber()),
+		Label:            fieldLabelName(pf),
+		Type:             t,
+		LongType:         lt,
+		FullType:         ft,
+		TypeKind:         typeKind(pf.GetType()),
+		Packed:           isPacked(pf),
+		DefaultValue:     pf.GetDefaultValue(),
+		IsOneof:          pf.OneofIndex != nil && !pf.GetProto3Optional(),
+		EmbeddedBlocks:   directive.Embeds(),
+		Required:         directive.Required(),
+		ReadOnly:         directive.ReadOnly() || behaviors["OUTPUT_ONLY"],
+		WriteOnly:        directive.WriteOnly() || behaviors["INPUT_ONLY"],
+		IsIdentifier:     directive.ID(),
+		NoSchema:         directive.NoSchema(),
+		DocsURL:          directive.DocsURL(),
+		DocsURLs:         directive.Docs(),
+		ReplacedBy:       directive.ReplacedBy(),
+		Since:            directive.Since(),
+		IsDynamicJSON:    dynamicJSONTypes[ft] != "",
+		DisplayType:      directive.Type(),
+		Descriptions:     directive.Translations(),
+		Description:      directive.Descrition,
+		RawComment:       pf.GetComments().String(),
+		DetachedComments: detachedComments(pf.GetComments(), commentPrefix),
+		IsPrimitive:      isPrimitive,
+		Options: mergeOptions(
+			extractOptions(pf.GetOptions()),
+			extensions.Transform(pf.OptionExtensions),
+			deprecatedOption(&directive),
+		),
+	}
+
+	m.HasCustomJSONName = hasCustomJSONName(pf.FieldDescriptorProto)
+
+	if pf.OneofIndex != nil {
+		index := int(pf.GetOneofIndex())
+		m.OneofIndex = &index
+		m.IsSyntheticOneof = pf.GetProto3Optional()
 	}
 
 	if m.IsOneof {
 		m.OneofDecl = oneofDecls[pf.GetOneofIndex()].GetName()
 	}
 
-	// Check if this is a map.
-	// See https://github.com/golang/protobuf/blob/master/protoc-gen-go/descriptor/descriptor.pb.go#L1556
-	// for more information
-	if m.Label == "repeated" &&
-		strings.Contains(m.LongType, ".") &&
-		strings.HasSuffix(m.Type, "Entry") &&
-		strings.HasSuffix(m.LongType, "Entry") &&
-		strings.HasSuffix(m.FullType, "Entry") {
+	// Editions express proto2's required keyword through the LEGACY_REQUIRED field presence.
+	if isEditionsFile(pf.GetFile()) && m.Label == "required" {
+		m.Required = true
+	}
+
+	if isMapField(pf) {
 		m.IsMap = true
+		m.TypeKind = typeKindMap
 	}
 
 	return m
 }
 
-func parseService(ps *protokit.ServiceDescriptor) *Service {
-	desc := description(ps.GetComments().String())
+// isMapField returns whether or not the field is a map, i.e. a repeated field of a map entry message. The compiler
+// generates those as nested messages of the message containing the field, with the map_entry option set. Messages that
+// merely happen to be named like one (e.g. FooEntry) don't count.
+func isMapField(pf *protokit.FieldDescriptor) bool {
+	if pf.GetLabel() != descriptor.FieldDescriptorProto_LABEL_REPEATED ||
+		pf.GetType() != descriptor.FieldDescriptorProto_TYPE_MESSAGE ||
+		pf.GetMessage() == nil {
+		return false
+	}
+
+	typeName := strings.TrimPrefix(pf.GetTypeName(), ".")
+	for _, nested := range pf.GetMessage().GetMessages() {
+		if nested.GetFullName() == typeName {
+			return nested.GetOptions().GetMapEntry()
+		}
+	}
+	return false
+}
+
+func parseService(ps *protokit.ServiceDescriptor, commentPrefix string) *Service {
+	desc := description(ps.GetComments().String(), commentPrefix)
 	directive := &Directive{Descrition: desc}
 
 	service := &Service{
-		Name:        ps.GetName(),
-		LongName:    ps.GetLongName(),
-		FullName:    ps.GetFullName(),
-		Title:       directive.Title(),
-		Exclude:     directive.Exclude(),
-		Options:     mergeOptions(extractOptions(ps.GetOptions()), extensions.Transform(ps.OptionExtensions)),
-		Description: directive.Descrition,
+		Name:             ps.GetName(),
+		LongName:         ps.GetLongName(),
+		FullName:         ps.GetFullName(),
+		EmbeddedBlocks:   directive.Embeds(),
+		Title:            directive.Title(),
+		Exclude:          directive.Exclude(),
+		Stability:        directive.Stability(),
+		Order:            directive.Order(),
+		DocsURL:          directive.DocsURL(),
+		DocsURLs:         directive.Docs(),
+		ReplacedBy:       directive.ReplacedBy(),
+		Since:            directive.Since(),
+		Descriptions:     directive.Translations(),
+		Description:      directive.Descrition,
+		RawComment:       ps.GetComments().String(),
+		DetachedComments: detachedComments(ps.GetComments(), commentPrefix),
+		Options: mergeOptions(
+			extractOptions(ps.GetOptions()),
+			extensions.Transform(ps.OptionExtensions),
+			deprecatedOption(directive),
+		),
 	}
 
 	for _, sm := range ps.Methods {
-		service.Methods = append(service.Methods, parseServiceMethod(sm))
+		service.Methods = append(service.Methods, parseServiceMethod(sm, service.FullName, commentPrefix))
 	}
 
 	return service
 }
 
-func parseServiceMethod(pm *protokit.MethodDescriptor) *ServiceMethod {
-	desc := description(pm.GetComments().String())
+func streamingKind(requestStreaming, responseStreaming bool) string {
+	switch {
+	case requestStreaming && responseStreaming:
+		return StreamingKindBidi
+	case requestStreaming:
+		return StreamingKindClient
+	case responseStreaming:
+		return StreamingKindServer
+	}
+	return StreamingKindUnary
+}
+
+// conventionalVerbs are the method name prefixes recognized by inferVerb.
+var conventionalVerbs = []string{"Get", "List", "Create", "Update", "Delete", "Batch"}
+
+// inferVerb returns the (lower cased) conventional verb the method name starts with, or "" when there's none. The verb
+// has to be followed by an upper case letter or the end of the name, so Getaway doesn't count as "get".
+func inferVerb(name string) string {
+	for _, verb := range conventionalVerbs {
+		rest := strings.TrimPrefix(name, verb)
+		if rest == name {
+			continue
+		}
+		if r, _ := utf8.DecodeRuneInString(rest); rest == "" || unicode.IsUpper(r) {
+			return strings.ToLower(verb)
+		}
+	}
+	return ""
+}
+
+func parseServiceMethod(pm *protokit.MethodDescriptor, serviceFullName, commentPrefix string) *ServiceMethod {
+	desc := description(pm.GetComments().String(), commentPrefix)
 
 	directive := &Directive{Descrition: desc}
 
-	return &ServiceMethod{
+	method := &ServiceMethod{
 		Name:              pm.GetName(),
+		FullMethodPath:    "/" + serviceFullName + "/" + pm.GetName(),
 		RequestType:       baseName(pm.GetInputType()),
-		RequestLongType:   strings.TrimPrefix(pm.GetInputType(), "."+pm.GetPackage()+"."),
-		RequestFullType:   strings.TrimPrefix(pm.GetInputType(), "."),
+		RequestLongType:   shortenType(pm.GetInputType(), pm.GetPackage()),
+		RequestFullType:   shortenType(pm.GetInputType(), ""),
 		RequestStreaming:  pm.GetClientStreaming(),
 		ResponseType:      baseName(pm.GetOutputType()),
-		ResponseLongType:  strings.TrimPrefix(pm.GetOutputType(), "."+pm.GetPackage()+"."),
-		ResponseFullType:  strings.TrimPrefix(pm.GetOutputType(), "."),
+		ResponseLongType:  shortenType(pm.GetOutputType(), pm.GetPackage()),
+		ResponseFullType:  shortenType(pm.GetOutputType(), ""),
 		ResponseStreaming: pm.GetServerStreaming(),
+		IdempotencyLevel:  pm.GetOptions().GetIdempotencyLevel().String(),
+		EmbeddedBlocks:    directive.Embeds(),
 		Action:            directive.Action(),
+		InferredVerb:      inferVerb(pm.GetName()),
 		Version:           directive.Version(),
+		Deadline:          directive.Deadline(),
+		Retryable:         directive.Retryable(),
+		SuccessStatus:     directive.Status(),
+		Stability:         directive.Stability(),
 		Title:             directive.Title(),
 		Exclude:           directive.Exclude(),
-		Options:           mergeOptions(extractOptions(pm.GetOptions()), extensions.Transform(pm.OptionExtensions)),
+		DocsURL:           directive.DocsURL(),
+		DocsURLs:          directive.Docs(),
+		ReplacedBy:        directive.ReplacedBy(),
+		Since:             directive.Since(),
+		ParamDocs:         directive.Params(),
+		Descriptions:      directive.Translations(),
 		Description:       directive.Descrition,
+		RawComment:        pm.GetComments().String(),
+		DetachedComments:  detachedComments(pm.GetComments(), commentPrefix),
+		Options: mergeOptions(
+			extractOptions(pm.GetOptions()),
+			extensions.Transform(pm.OptionExtensions),
+			deprecatedOption(directive),
+		),
+	}
+
+	if method.RequestStreaming {
+		method.RequestStreamNote = streamNote(method.RequestLongType)
+	}
+	if method.ResponseStreaming {
+		method.ResponseStreamNote = streamNote(method.ResponseLongType)
+	}
+
+	method.StreamingKind = streamingKind(method.RequestStreaming, method.ResponseStreaming)
+	method.RequestWellKnownKind = wellKnownKinds[method.RequestFullType]
+	method.RequestIsWellKnown = method.RequestWellKnownKind != ""
+	method.ResponseWellKnownKind = wellKnownKinds[method.ResponseFullType]
+	method.ResponseIsWellKnown = method.ResponseWellKnownKind != ""
+	method.Safe = method.IdempotencyLevel == IdempotencyNoSideEffects
+	method.Idempotent = method.Safe || method.IdempotencyLevel == IdempotencyIdempotent
+
+	return method
+}
+
+func streamNote(longType string) string {
+	return "stream of " + longType
+}
+
+// hexNumber formats a decimal enum number as hex (e.g. "255" becomes "0xff"). Unparsable input yields "".
+func hexNumber(number string) string {
+	n, err := strconv.ParseInt(number, 10, 64)
+	if err != nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%#x", n)
+}
+
+// syntaxName returns the syntax of a file. protoc leaves the syntax empty for proto2 files.
+func syntaxName(syntax string) string {
+	if syntax == "" {
+		return "proto2"
+	}
+
+	return syntax
+}
+
+// jsonName returns the name of the field in the JSON mapping. protoc always sets json_name, when it's missing the name
+// is derived the same way protoc does it (lowerCamelCase).
+func jsonName(pf *descriptor.FieldDescriptorProto) string {
+	if pf.JsonName != nil {
+		return pf.GetJsonName()
+	}
+	return defaultJSONName(pf.GetName())
+}
+
+// hasCustomJSONName returns whether or not the field's json_name was set explicitly, i.e. differs from the default.
+func hasCustomJSONName(pf *descriptor.FieldDescriptorProto) bool {
+	return pf.JsonName != nil && pf.GetJsonName() != defaultJSONName(pf.GetName())
+}
+
+// defaultJSONName derives the JSON name of a field from its name the way protoc does it (lowerCamelCase).
+func defaultJSONName(name string) string {
+	var b strings.Builder
+	upper := false
+	for _, r := range name {
+		switch {
+		case r == '_':
+			upper = true
+		case upper && 'a' <= r && r <= 'z':
+			b.WriteRune(r - 'a' + 'A')
+			upper = false
+		default:
+			b.WriteRune(r)
+			upper = false
+		}
 	}
+
+	return b.String()
 }
 
 func baseName(name string) string {
@@ -676,14 +2615,63 @@ func baseName(name string) string {
 	return parts[len(parts)-1]
 }
 
+func fieldLabelName(pf *protokit.FieldDescriptor) string {
+	if isEditionsFile(pf.GetFile()) {
+		return editionLabelName(pf)
+	}
+
+	return labelName(pf.GetLabel(), pf.IsProto3(), pf.GetProto3Optional())
+}
+
+// labelName returns the label of a field as written in the proto file. Singular proto3 fields don't have one, unless
+// they're declared `optional` (explicit presence), in which case the label is "optional" like in proto2.
 func labelName(lbl descriptor.FieldDescriptorProto_Label, proto3 bool, proto3Opt bool) string {
-	if proto3 && !proto3Opt && lbl != descriptor.FieldDescriptorProto_LABEL_REPEATED {
+	switch {
+	case lbl == descriptor.FieldDescriptorProto_LABEL_REPEATED:
+		return "repeated"
+	case proto3Opt:
+		return "optional"
+	case proto3:
 		return ""
 	}
 
 	return strings.ToLower(strings.TrimPrefix(lbl.String(), "LABEL_"))
 }
 
+func isPacked(pf *protokit.FieldDescriptor) bool {
+	if pf.GetLabel() != descriptor.FieldDescriptorProto_LABEL_REPEATED {
+		return false
+	}
+
+	switch pf.GetType() {
+	case descriptor.FieldDescriptorProto_TYPE_STRING,
+		descriptor.FieldDescriptorProto_TYPE_BYTES,
+		descriptor.FieldDescriptorProto_TYPE_MESSAGE,
+		descriptor.FieldDescriptorProto_TYPE_GROUP:
+		return false
+	}
+
+	if opts := pf.GetOptions(); opts != nil && opts.Packed != nil {
+		return opts.GetPacked()
+	}
+	if isEditionsFile(pf.GetFile()) {
+		return editionPacked(pf)
+	}
+
+	return pf.IsProto3()
+}
+
+func typeKind(t descriptor.FieldDescriptorProto_Type) string {
+	switch t {
+	case descriptor.FieldDescriptorProto_TYPE_MESSAGE, descriptor.FieldDescriptorProto_TYPE_GROUP:
+		return typeKindMessage
+	case descriptor.FieldDescriptorProto_TYPE_ENUM:
+		return typeKindEnum
+	}
+
+	return typeKindScalar
+}
+
 type typeContainer interface {
 	GetType() descriptor.FieldDescriptorProto_Type
 	GetTypeName() string
@@ -694,34 +2682,76 @@ func parseType(tc typeContainer) (string, string, string) {
 	name := tc.GetTypeName()
 
 	if strings.HasPrefix(name, ".") {
-		name = strings.TrimPrefix(name, ".")
-		return baseName(name), strings.TrimPrefix(name, tc.GetPackage()+"."), name
+		name = shortenType(name, "")
+		return baseName(name), shortenType(name, tc.GetPackage()), name
 	}
 
 	name = strings.ToLower(strings.TrimPrefix(tc.GetType().String(), "TYPE_"))
 	return name, name, name
 }
 
-func description(comment string) string {
+// shortenType returns the name of a type relative to the given package, i.e. without the package prefix when the type
+// is defined in that package or one of its sub-packages (e.g. "v1.Thing" for "com.example.v1.Thing" relative to
+// "com.example"). Types of other packages keep their full name. The leading dot of fully-qualified names in
+// descriptors is dropped either way, so an empty package yields the full name.
+func shortenType(fullName, relativeToPackage string) string {
+	name := strings.TrimPrefix(fullName, ".")
+	if relativeToPackage == "" {
+		return name
+	}
+
+	return strings.TrimPrefix(name, relativeToPackage+".")
+}
+
+// detachedComments returns the leading detached comments of an element, i.e. the comments separated from it (and each
+// other) by blank lines, such as section headers. The result is empty, not nil, when there are none.
+func detachedComments(comment *protokit.Comment, commentPrefix string) []string {
+	detached := make([]string, 0, len(comment.GetDetached()))
+	for _, c := range comment.GetDetached() {
+		if desc := description(c, commentPrefix); desc != "" {
+			detached = append(detached, desc)
+		}
+	}
+	return detached
+}
+
+// description cleans up a comment for use as a description. When commentPrefix is set (see
+// TemplateOptions.CommentPrefix), it's stripped from the comment, and comments without it are ignored.
+func description(comment, commentPrefix string) string {
 	val := strings.TrimLeft(comment, "*/\n ")
+	if commentPrefix != "" {
+		if !strings.HasPrefix(val, commentPrefix) {
+			return ""
+		}
+		val = strings.TrimLeft(strings.TrimPrefix(val, commentPrefix), " \t")
+	}
 
 	// indent json
-	val = IndentJsonInComment(val, "```json", "```")
+	val = indentJSONFences(val)
 
 	return val
 }
 
 func IndentJson(in string) string {
+	return indentJSON(in, "")
+}
+
+// indentJSON formats in, with every line starting with prefix. Invalid JSON is returned as is.
+func indentJSON(in string, prefix string) string {
 	var str bytes.Buffer
-	err := json.Indent(&str, []byte(in), "", "  ")
+	err := json.Indent(&str, []byte(in), prefix, "  ")
 	if err != nil {
 		return in
 	}
-	out := str.String()
+	out := prefix + str.String()
 	return out
 }
 
 func IndentJsonInComment(comment string, beginTag string, endTag string) string {
+	return indentJSONBlocks(comment, beginTag, endTag, "")
+}
+
+func indentJSONBlocks(comment string, beginTag string, endTag string, prefix string) string {
 	originComment := comment
 
 	res := ""
@@ -739,7 +2769,7 @@ func IndentJsonInComment(comment string, beginTag string, endTag string) string
 		} else {
 			jsonStr := comment[0:endIdx]
 
-			intendedJson := IndentJson(jsonStr)
+			intendedJson := indentJSON(jsonStr, prefix)
 
 			res += "\n" + intendedJson
 
@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+	plugin_go "github.com/golang/protobuf/protoc-gen-go/plugin"
 	. "github.com/pseudomuto/protoc-gen-doc"
 	"github.com/pseudomuto/protoc-gen-doc/extensions"
 	"github.com/pseudomuto/protokit"
@@ -135,10 +137,80 @@ func TestTemplateProperties(t *testing.T) {
 	require.Len(t, template.Files, 2)
 }
 
+func TestMarshalCanonical(t *testing.T) {
+	reversed := &Template{Files: []*File{vehicleFile, bookingFile}, Scalars: template.Scalars}
+
+	expected, err := template.MarshalCanonical()
+	require.NoError(t, err)
+
+	actual, err := reversed.MarshalCanonical()
+	require.NoError(t, err)
+	require.Equal(t, string(expected), string(actual))
+
+	var decoded Template
+	require.NoError(t, json.Unmarshal(actual, &decoded))
+	require.Equal(t, "Booking.proto", decoded.Files[0].Name)
+	require.Equal(t, "Vehicle.proto", decoded.Files[1].Name)
+}
+
+func TestUsedScalars(t *testing.T) {
+	tmpl := newTestTemplate(&descriptor.FileDescriptorProto{
+		Name: proto.String("scalars.proto"),
+		MessageType: []*descriptor.DescriptorProto{
+			{
+				Name: proto.String("Thing"),
+				Field: []*descriptor.FieldDescriptorProto{
+					newTestField("id", 1, descriptor.FieldDescriptorProto_TYPE_INT64, ""),
+					newTestField("name", 2, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+					newTestField("other", 3, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".Thing"),
+				},
+			},
+		},
+	})
+
+	var used []string
+	for _, scalar := range tmpl.UsedScalars() {
+		used = append(used, scalar.ProtoType)
+	}
+	require.Equal(t, []string{"int64", "string"}, used)
+
+	empty := newTestTemplate(&descriptor.FileDescriptorProto{Name: proto.String("empty.proto")})
+	require.Nil(t, empty.UsedScalars())
+
+	all := &Template{Scalars: template.Scalars, Files: []*File{{Messages: []*Message{{}}}}}
+	for _, scalar := range template.Scalars {
+		all.Files[0].Messages[0].Fields = append(all.Files[0].Messages[0].Fields, &MessageField{Type: scalar.ProtoType})
+	}
+	require.Equal(t, template.Scalars, all.UsedScalars())
+}
+
+func TestUsedScalarsOnly(t *testing.T) {
+	fd := &descriptor.FileDescriptorProto{
+		Name:    proto.String("scalars.proto"),
+		Package: proto.String("test"),
+		MessageType: []*descriptor.DescriptorProto{{
+			Name:  proto.String("Thing"),
+			Field: []*descriptor.FieldDescriptorProto{newTestField("other", 1, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".test.Thing")},
+		}},
+	}
+
+	tmpl := newTestTemplateWithOptions(TemplateOptions{UsedScalarsOnly: true}, fd)
+	require.Nil(t, tmpl.Scalars)
+
+	for _, kind := range []RenderType{RenderTypeMarkdown, RenderTypeHTML, RenderTypeDocBook} {
+		output, err := RenderTemplate(kind, tmpl, "")
+		require.NoError(t, err)
+		require.NotContains(t, string(output), "Scalar Value Types")
+	}
+
+	require.Equal(t, template.Scalars, newTestTemplate(fd).Scalars)
+}
+
 func TestFileProperties(t *testing.T) {
 	require.Equal(t, "Booking.proto", bookingFile.Name)
 	require.Equal(t, "Booking related messages.\n\nThis file is really just an example. The data model is completely\nfictional.", bookingFile.Description)
 	require.Equal(t, "com.example", bookingFile.Package)
+	require.Equal(t, "proto2", bookingFile.Syntax)
 	require.True(t, bookingFile.HasEnums)
 	require.True(t, bookingFile.HasExtensions)
 	require.True(t, bookingFile.HasMessages)
@@ -147,6 +219,38 @@ func TestFileProperties(t *testing.T) {
 	require.True(t, *bookingFile.Option(E_ExtendFile.Name).(*bool))
 }
 
+func TestFileExcludeDirective(t *testing.T) {
+	require.False(t, bookingFile.Exclude)
+
+	tmpl := newTestTemplate(
+		&descriptor.FileDescriptorProto{
+			Name:    proto.String("internal.proto"),
+			Package: proto.String("test"),
+			Syntax:  proto.String("proto3"),
+			SourceCodeInfo: &descriptor.SourceCodeInfo{
+				Location: []*descriptor.SourceCodeInfo_Location{
+					{Path: []int32{12}, LeadingComments: proto.String(" Shared types. @exclude\n")},
+				},
+			},
+		},
+		&descriptor.FileDescriptorProto{
+			Name:    proto.String("shared.proto"),
+			Package: proto.String("test"),
+			Syntax:  proto.String("proto3"),
+			SourceCodeInfo: &descriptor.SourceCodeInfo{
+				Location: []*descriptor.SourceCodeInfo_Location{
+					{Path: []int32{2}, LeadingComments: proto.String(" @exclude\n")},
+				},
+			},
+		},
+	)
+
+	require.True(t, tmpl.Files[0].Exclude)
+	require.Equal(t, "Shared types. ", tmpl.Files[0].Description)
+	require.True(t, tmpl.Files[1].Exclude)
+	require.Empty(t, tmpl.Files[1].Description)
+}
+
 func TestFileEnumProperties(t *testing.T) {
 	enum := findEnum("BookingStatus.StatusCode", bookingFile)
 	require.Equal(t, "StatusCode", enum.Name)
@@ -156,8 +260,8 @@ func TestFileEnumProperties(t *testing.T) {
 	require.Len(t, enum.Values, 2)
 
 	expectedValues := []*EnumValue{
-		{Name: "OK", Number: "200", Description: "OK result."},
-		{Name: "BAD_REQUEST", Number: "400", Description: "BAD result."},
+		{Name: "OK", Number: "200", NumberHex: "0xc8", Description: "OK result.", RawComment: "OK result."},
+		{Name: "BAD_REQUEST", Number: "400", NumberHex: "0x190", Description: "BAD result.", RawComment: "BAD result."},
 	}
 
 	for idx, value := range enum.Values {
@@ -209,6 +313,25 @@ func TestMessageProperties(t *testing.T) {
 	require.NotEmpty(t, msg.FieldsWithOption(E_ExtendField.Name))
 }
 
+func TestMessageRequestResponseUsage(t *testing.T) {
+	msg := findMessage("FindVehicleById", vehicleFile)
+	require.True(t, msg.IsRequest)
+	require.False(t, msg.IsResponse)
+
+	msg = findMessage("Model", vehicleFile)
+	require.True(t, msg.IsRequest)
+	require.True(t, msg.IsResponse)
+
+	msg = findMessage("Booking", bookingFile)
+	require.True(t, msg.IsRequest)
+	require.False(t, findMessage("BookingStatus", bookingFile).IsRequest)
+	require.True(t, findMessage("BookingStatus", bookingFile).IsResponse)
+
+	msg = findMessage("Manufacturer", vehicleFile)
+	require.False(t, msg.IsRequest)
+	require.False(t, msg.IsResponse)
+}
+
 func TestNestedMessageProperties(t *testing.T) {
 	msg := findMessage("Vehicle.Category", vehicleFile)
 	require.Equal(t, "Category", msg.Name)
@@ -224,6 +347,96 @@ func TestMultiplyNestedMessages(t *testing.T) {
 	require.NotNil(t, findMessage("Vehicle.Engine.Stats", vehicleFile))
 }
 
+func TestLabelNames(t *testing.T) {
+	field := findField("rates", findMessage("Vehicle", vehicleFile))
+	require.Equal(t, "repeated", field.Label)
+	require.Equal(t, "repeated", field.LabelDisplay)
+
+	tmpl := newFixtureTemplateWithOptions(TemplateOptions{
+		LabelNames: map[string]string{"repeated": "list"},
+	})
+
+	field = findField("rates", findMessage("Vehicle", tmpl.Files[1]))
+	require.Equal(t, "repeated", field.Label)
+	require.Equal(t, "list", field.LabelDisplay)
+
+	ext := findExtension("BookingStatus.country", tmpl.Files[0])
+	require.Equal(t, "optional", ext.LabelDisplay)
+}
+
+func TestFieldTypeDeprecated(t *testing.T) {
+	require.False(t, findField("model", findMessage("Vehicle", vehicleFile)).TypeDeprecated)
+	require.False(t, findField("id", findMessage("Vehicle", vehicleFile)).TypeDeprecated)
+
+	tmpl := newTestTemplate(&descriptor.FileDescriptorProto{
+		Name:    proto.String("deprecated.proto"),
+		Package: proto.String("test"),
+		MessageType: []*descriptor.DescriptorProto{
+			{
+				Name: proto.String("Thing"),
+				Field: []*descriptor.FieldDescriptorProto{
+					newTestField("old", 1, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".test.Old"),
+					newTestField("kind", 2, descriptor.FieldDescriptorProto_TYPE_ENUM, ".test.Kind"),
+					newTestField("name", 3, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+				},
+			},
+			{Name: proto.String("Old"), Options: &descriptor.MessageOptions{Deprecated: proto.Bool(true)}},
+		},
+		EnumType: []*descriptor.EnumDescriptorProto{{
+			Name:    proto.String("Kind"),
+			Value:   []*descriptor.EnumValueDescriptorProto{{Name: proto.String("KIND_UNSPECIFIED"), Number: proto.Int32(0)}},
+			Options: &descriptor.EnumOptions{Deprecated: proto.Bool(true)},
+		}},
+	})
+
+	msg := findMessage("Thing", tmpl.Files[0])
+	require.True(t, findField("old", msg).TypeDeprecated)
+	require.True(t, findField("kind", msg).TypeDeprecated)
+	require.False(t, findField("name", msg).TypeDeprecated)
+}
+
+func TestMethodInlineFields(t *testing.T) {
+	method := findServiceMethod("GetVehicle", findService("VehicleService", vehicleFile))
+	require.Len(t, method.RequestFields, 1)
+	require.Equal(t, "id", method.RequestFields[0].Name)
+
+	engine := method.ResponseFields[5]
+	require.Equal(t, "engine", engine.Name)
+	require.Equal(t, 1, engine.Depth)
+	require.True(t, engine.IsLink)
+	require.Empty(t, engine.Fields)
+
+	tmpl := newFixtureTemplateWithOptions(TemplateOptions{MaxInlineDepth: 2})
+
+	method = findServiceMethod("GetVehicle", findService("VehicleService", tmpl.Files[1]))
+	engine = method.ResponseFields[5]
+	require.False(t, engine.IsLink)
+	require.Len(t, engine.Fields, 3)
+
+	stats := engine.Fields[2]
+	require.Equal(t, "stats", stats.Name)
+	require.Equal(t, 2, stats.Depth)
+	require.True(t, stats.IsLink)
+	require.Empty(t, stats.Fields)
+	require.False(t, engine.Fields[1].IsLink)
+}
+
+func TestMaxDefaultValueLen(t *testing.T) {
+	ext := findExtension("BookingStatus.country", bookingFile)
+	require.Equal(t, "china", ext.DefaultValue)
+	require.Equal(t, "china", ext.DefaultValueFull)
+
+	tmpl := newFixtureTemplateWithOptions(TemplateOptions{MaxDefaultValueLen: 3})
+
+	ext = findExtension("BookingStatus.country", tmpl.Files[0])
+	require.Equal(t, "chi...", ext.DefaultValue)
+	require.Equal(t, "china", ext.DefaultValueFull)
+
+	field := findField("payment_received", findMessage("Booking", tmpl.Files[0]))
+	require.Equal(t, "fal...", field.DefaultValue)
+	require.Equal(t, "false", field.DefaultValueFull)
+}
+
 func TestMessageExtensionProperties(t *testing.T) {
 	msg := findMessage("Booking", bookingFile)
 	require.Len(t, msg.Extensions, 1)
@@ -322,6 +535,177 @@ func TestFieldProperties(t *testing.T) {
 	require.Equal(t, "drivers", field.OneofDecl)
 }
 
+func TestFieldTypeAnchors(t *testing.T) {
+	vehicle := findMessage("Vehicle", vehicleFile)
+
+	field := findField("model", vehicle)
+	require.Equal(t, "message", field.TypeKind)
+	require.Equal(t, findMessage("Model", vehicleFile).Anchor(), field.TypeAnchor)
+
+	field = findField("category", vehicle)
+	require.Equal(t, "message", field.TypeKind)
+	require.Equal(t, "com.example.Vehicle.Category", field.TypeAnchor)
+
+	field = findField("fuel_type", findMessage("Vehicle.Engine", vehicleFile))
+	require.Equal(t, "enum", field.TypeKind)
+	require.Equal(t, "com.example.Vehicle.Engine.FuelType", field.TypeAnchor)
+
+	field = findField("properties", vehicle)
+	require.Equal(t, "map", field.TypeKind)
+	require.Empty(t, field.TypeAnchor)
+
+	field = findField("rates", vehicle)
+	require.Equal(t, "scalar", field.TypeKind)
+	require.Empty(t, field.TypeAnchor)
+}
+
+func TestInlineEnumValues(t *testing.T) {
+	field := findField("fuel_type", findMessage("Vehicle.Engine", vehicleFile))
+	require.Nil(t, field.EnumValues)
+
+	tmpl := newFixtureTemplateWithOptions(TemplateOptions{InlineEnumValues: true})
+
+	engine := findMessage("Vehicle.Engine", tmpl.Files[1])
+	field = findField("fuel_type", engine)
+	require.Equal(t, findEnum("Vehicle.Engine.FuelType", tmpl.Files[1]).Values, field.EnumValues)
+	require.Len(t, field.EnumValues, 4)
+	require.Nil(t, findField("stats", engine).EnumValues)
+}
+
+func TestPackedFields(t *testing.T) {
+	vehicle := findMessage("Vehicle", vehicleFile)
+	require.True(t, findField("rates", vehicle).Packed)
+	require.False(t, findField("properties", vehicle).Packed)
+	require.False(t, findField("mileage", vehicle).Packed)
+	require.False(t, findField("ingredients", findMessage("Cookie", cookieFile)).Packed)
+
+	repeated := descriptor.FieldDescriptorProto_LABEL_REPEATED
+	unpacked := newTestField("unpacked", 1, descriptor.FieldDescriptorProto_TYPE_INT32, "")
+	unpacked.Label = &repeated
+	unpacked.Options = &descriptor.FieldOptions{Packed: proto.Bool(false)}
+	defaulted := newTestField("defaulted", 2, descriptor.FieldDescriptorProto_TYPE_INT32, "")
+	defaulted.Label = &repeated
+	packed := newTestField("packed", 3, descriptor.FieldDescriptorProto_TYPE_INT32, "")
+	packed.Label = &repeated
+	packed.Options = &descriptor.FieldOptions{Packed: proto.Bool(true)}
+
+	tmpl := newTestTemplate(&descriptor.FileDescriptorProto{
+		Name:    proto.String("packed.proto"),
+		Package: proto.String("test"),
+		MessageType: []*descriptor.DescriptorProto{{
+			Name:  proto.String("Numbers"),
+			Field: []*descriptor.FieldDescriptorProto{unpacked, defaulted, packed},
+		}},
+	})
+
+	msg := findMessage("Numbers", tmpl.Files[0])
+	require.False(t, findField("unpacked", msg).Packed)
+	require.False(t, findField("defaulted", msg).Packed) // proto2
+	require.True(t, findField("packed", msg).Packed)
+}
+
+func TestMapFieldValues(t *testing.T) {
+	field := findField("properties", findMessage("Vehicle", vehicleFile))
+	require.Equal(t, "string", field.MapKeyType)
+	require.Equal(t, "string", field.MapValueType)
+	require.False(t, field.MapValueIsMessage)
+	require.Empty(t, field.MapValueAnchor)
+
+	repeated := descriptor.FieldDescriptorProto_LABEL_REPEATED
+	garage := newTestField("cars", 1, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".test.Garage.CarsEntry")
+	garage.Label = &repeated
+	colors := newTestField("colors", 2, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".test.Garage.ColorsEntry")
+	colors.Label = &repeated
+
+	tmpl := newTestTemplate(&descriptor.FileDescriptorProto{
+		Name:    proto.String("garage.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptor.DescriptorProto{
+			{Name: proto.String("Car")},
+			{
+				Name:  proto.String("Garage"),
+				Field: []*descriptor.FieldDescriptorProto{garage, colors},
+				NestedType: []*descriptor.DescriptorProto{
+					newTestMapEntry("CarsEntry", newTestField("", 0, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".test.Car")),
+					newTestMapEntry("ColorsEntry", newTestField("", 0, descriptor.FieldDescriptorProto_TYPE_ENUM, ".test.Color")),
+				},
+			},
+		},
+		EnumType: []*descriptor.EnumDescriptorProto{{
+			Name:  proto.String("Color"),
+			Value: []*descriptor.EnumValueDescriptorProto{{Name: proto.String("RED"), Number: proto.Int32(0)}},
+		}},
+	})
+
+	msg := findMessage("Garage", tmpl.Files[0])
+
+	field = findField("cars", msg)
+	require.True(t, field.IsMap)
+	require.Equal(t, "string", field.MapKeyType)
+	require.Equal(t, "Car", field.MapValueType)
+	require.True(t, field.MapValueIsMessage)
+	require.Equal(t, "test.Car", field.MapValueAnchor)
+
+	field = findField("colors", msg)
+	require.Equal(t, "Color", field.MapValueType)
+	require.False(t, field.MapValueIsMessage)
+	require.Equal(t, "test.Color", field.MapValueAnchor)
+}
+
+func TestMapTypeString(t *testing.T) {
+	vehicle := findMessage("Vehicle", vehicleFile)
+	require.Equal(t, "map<string, string>", findField("properties", vehicle).MapTypeString())
+	require.Equal(t, findField("id", vehicle).LongType, findField("id", vehicle).MapTypeString())
+
+	field := MessageField{LongType: "Garage.CarsEntry", IsMap: true, MapKeyType: "int32", MapValueType: "Car"}
+	require.Equal(t, "map<int32, Car>", field.MapTypeString())
+
+	field = MessageField{LongType: "Garage.CarsEntry", IsMap: true}
+	require.Equal(t, "Garage.CarsEntry", field.MapTypeString())
+}
+
+func TestMessageOneofs(t *testing.T) {
+	msg := findMessage("Vehicle", vehicleFile)
+	require.Len(t, msg.Oneofs, 2)
+	require.Equal(t, "travel", msg.Oneofs[0].Name)
+	require.Empty(t, msg.Oneofs[0].Description)
+	require.Equal(t, []*MessageField{findField("kilometers", msg), findField("lightyears", msg)}, msg.Oneofs[0].Fields)
+	require.Equal(t, []string{"kilometers", "lightyears"}, msg.Oneofs[0].FieldNames)
+	require.Equal(t, "drivers", msg.Oneofs[1].Name)
+
+	data, err := json.Marshal(msg.Oneofs[0])
+	require.NoError(t, err)
+	require.JSONEq(t, `{"name":"travel","description":"","hasDescription":false,"rawComment":"","exhaustive":false,"fields":["kilometers","lightyears"]}`, string(data))
+
+	// synthetic oneofs of proto3 optional fields aren't groups
+	require.Empty(t, findMessage("Cookie", cookieFile).Oneofs)
+
+	choice := newTestField("card", 1, descriptor.FieldDescriptorProto_TYPE_STRING, "")
+	choice.OneofIndex = proto.Int32(0)
+
+	tmpl := newTestTemplate(&descriptor.FileDescriptorProto{
+		Name:    proto.String("payment.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptor.DescriptorProto{{
+			Name:      proto.String("Payment"),
+			Field:     []*descriptor.FieldDescriptorProto{choice},
+			OneofDecl: []*descriptor.OneofDescriptorProto{{Name: proto.String("method")}},
+		}},
+		SourceCodeInfo: &descriptor.SourceCodeInfo{
+			Location: []*descriptor.SourceCodeInfo_Location{
+				{Path: []int32{4, 0, 8, 0}, LeadingComments: proto.String(" How the payment is made.\n")},
+			},
+		},
+	})
+
+	msg = findMessage("Payment", tmpl.Files[0])
+	require.Len(t, msg.Oneofs, 1)
+	require.Equal(t, "method", msg.Oneofs[0].Name)
+	require.Equal(t, "How the payment is made.", msg.Oneofs[0].Description)
+}
+
 func TestFieldPropertiesProto3(t *testing.T) {
 	msg := findMessage("Model", vehicleFile)
 
@@ -403,6 +787,26 @@ func TestServiceProperties(t *testing.T) {
 	require.NotEmpty(t, service.MethodsWithOption(E_ExtendMethod.Name))
 }
 
+func TestServiceMethodsByAction(t *testing.T) {
+	service := Service{Methods: []*ServiceMethod{
+		{Name: "ListImages", Action: "list"},
+		{Name: "Ping"},
+		{Name: "GetImage", Action: "get"},
+		{Name: "ListAlbums", Action: "list"},
+	}}
+
+	groups := service.MethodsByAction()
+	require.Len(t, groups, 3)
+	require.Equal(t, "get", groups[0].Action)
+	require.Equal(t, []*ServiceMethod{service.Methods[2]}, groups[0].Methods)
+	require.Equal(t, "list", groups[1].Action)
+	require.Equal(t, []*ServiceMethod{service.Methods[0], service.Methods[3]}, groups[1].Methods)
+	require.Equal(t, "", groups[2].Action)
+	require.Equal(t, []*ServiceMethod{service.Methods[1]}, groups[2].Methods)
+
+	require.Empty(t, Service{}.MethodsByAction())
+}
+
 func TestServiceMethodProperties(t *testing.T) {
 	service := findService("VehicleService", vehicleFile)
 
@@ -420,6 +824,7 @@ func TestServiceMethodProperties(t *testing.T) {
 
 	method = findServiceMethod("GetVehicle", service)
 	require.Equal(t, "GetVehicle", method.Name)
+	require.Equal(t, "/com.example.VehicleService/GetVehicle", method.FullMethodPath)
 	require.Equal(t, "Looks up a vehicle by id.", method.Description)
 	require.Equal(t, "FindVehicleById", method.RequestType)
 	require.Equal(t, "FindVehicleById", method.RequestLongType)
@@ -443,6 +848,18 @@ func TestExcludedComments(t *testing.T) {
 	require.Equal(t, "the id of this message.", findField("id", message).Description)
 }
 
+func TestRawComment(t *testing.T) {
+	message := findMessage("ExcludedMessage", vehicleFile)
+	require.Equal(t, "*\n@exclude\nThis comment won't be rendered", message.RawComment)
+	require.Equal(t, "@exclude the name of this message", findField("name", message).RawComment)
+
+	service := findService("ImageService", vehicleFile)
+	require.Contains(t, service.RawComment, "@title 图像理解")
+	require.NotContains(t, service.Description, "@title")
+
+	require.Equal(t, "OK result.", findEnum("BookingStatus.StatusCode", bookingFile).Values[0].RawComment)
+}
+
 func TestDirective(t *testing.T) {
 	service := findService("ImageService", vehicleFile)
 	require.Equal(t, "图像理解", service.Title)
@@ -451,6 +868,298 @@ func TestDirective(t *testing.T) {
 	require.Equal(t, "图像理解", method.Title)
 }
 
+func TestFileCategories(t *testing.T) {
+	categorized := func(name, comment string, path int32) *descriptor.FileDescriptorProto {
+		return &descriptor.FileDescriptorProto{
+			Name:   proto.String(name),
+			Syntax: proto.String("proto3"),
+			SourceCodeInfo: &descriptor.SourceCodeInfo{
+				Location: []*descriptor.SourceCodeInfo_Location{
+					{Path: []int32{path}, LeadingComments: proto.String(comment)},
+				},
+			},
+		}
+	}
+
+	tmpl := newTestTemplate(
+		categorized("invoices.proto", " Invoices.\n @category Billing\n", 12),
+		categorized("misc.proto", " Other things.\n", 12),
+		categorized("payments.proto", " @category Billing\n", 2),
+		categorized("users.proto", " @category Accounts\n", 12),
+	)
+
+	require.Equal(t, "Billing", tmpl.Files[0].Category)
+	require.Equal(t, "Invoices.\n", tmpl.Files[0].Description)
+	require.Empty(t, tmpl.Files[1].Category)
+	require.Equal(t, "Billing", tmpl.Files[2].Category)
+
+	groups := tmpl.FilesByCategory()
+	require.Len(t, groups, 3)
+	require.Equal(t, "Accounts", groups[0].Category)
+	require.Equal(t, []*File{tmpl.Files[3]}, groups[0].Files)
+	require.Equal(t, "Billing", groups[1].Category)
+	require.Equal(t, []*File{tmpl.Files[0], tmpl.Files[2]}, groups[1].Files)
+	require.Empty(t, groups[2].Category)
+	require.Equal(t, []*File{tmpl.Files[1]}, groups[2].Files)
+
+	directive := &Directive{Descrition: "Invoices.\n@category Billing API \nMore."}
+	require.Equal(t, "Billing API", directive.Category())
+	require.Equal(t, "Invoices.\n\nMore.", directive.Descrition)
+
+	directive = &Directive{Descrition: "Uses @categoryId.\nMore."}
+	require.Empty(t, directive.Category())
+	require.Equal(t, "Uses @categoryId.\nMore.", directive.Descrition)
+}
+
+func TestFileTitleAndVersion(t *testing.T) {
+	tmpl := newTestTemplate(
+		&descriptor.FileDescriptorProto{
+			Name:   proto.String("billing.proto"),
+			Syntax: proto.String("proto3"),
+			SourceCodeInfo: &descriptor.SourceCodeInfo{
+				Location: []*descriptor.SourceCodeInfo_Location{
+					{Path: []int32{12}, LeadingComments: proto.String(" Billing API.\n @title Billing\n @version v2\n")},
+				},
+			},
+		},
+		&descriptor.FileDescriptorProto{
+			Name:   proto.String("users.proto"),
+			Syntax: proto.String("proto3"),
+			SourceCodeInfo: &descriptor.SourceCodeInfo{
+				Location: []*descriptor.SourceCodeInfo_Location{
+					{Path: []int32{2}, LeadingComments: proto.String(" @version v1\n")},
+				},
+			},
+		},
+	)
+
+	require.Equal(t, "Billing", tmpl.Files[0].Title)
+	require.Equal(t, "v2", tmpl.Files[0].Version)
+	require.Equal(t, "Billing API.\n\n", tmpl.Files[0].Description)
+	require.Empty(t, tmpl.Files[1].Title)
+	require.Equal(t, "v1", tmpl.Files[1].Version)
+
+	require.Empty(t, bookingFile.Title)
+	require.Empty(t, bookingFile.Version)
+}
+
+func TestHexDirective(t *testing.T) {
+	directive := &Directive{Descrition: "Permission bits.\n@hex"}
+	require.True(t, directive.Hex())
+	require.Equal(t, "Permission bits.\n", directive.Descrition)
+
+	directive = &Directive{Descrition: "Plain enum."}
+	require.False(t, directive.Hex())
+
+	directive = &Directive{Descrition: "See @hexadecimal and @hexdump."}
+	require.False(t, directive.Hex())
+	require.Equal(t, "See @hexadecimal and @hexdump.", directive.Descrition)
+
+	require.False(t, findEnum("BookingStatus.StatusCode", bookingFile).Hex)
+}
+
+func TestTypeDirective(t *testing.T) {
+	directive := &Directive{Descrition: "The encoded token.\n@type  JWT\n"}
+	require.Equal(t, "JWT", directive.Type())
+	require.Equal(t, "The encoded token.\n\n", directive.Descrition)
+
+	directive = &Directive{Descrition: "No override."}
+	require.Empty(t, directive.Type())
+	require.Empty(t, findField("rates", findMessage("Vehicle", vehicleFile)).DisplayType)
+
+	for _, desc := range []string{
+		`An Any, e.g. {"@type": "type.googleapis.com/test.Thing", "id": 1}`,
+		"An Any, e.g.\n```json\n{\n  \"@type\": \"type.googleapis.com/test.Thing\"\n}\n```",
+		"Not a directive: @types and @typed.",
+	} {
+		directive = &Directive{Descrition: desc}
+		require.Empty(t, directive.Type(), desc)
+		require.Equal(t, desc, directive.Descrition)
+	}
+
+	directive = &Directive{Descrition: `The payload. @type Any {"@type": "x"}`}
+	require.Equal(t, `Any {"@type": "x"}`, directive.Type())
+	require.Equal(t, "The payload. ", directive.Descrition)
+
+	field := newTestField("payload", 1, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.Any")
+	tmpl := newTestTemplate(&descriptor.FileDescriptorProto{
+		Name:        proto.String("any.proto"),
+		Package:     proto.String("test"),
+		MessageType: []*descriptor.DescriptorProto{{Name: proto.String("Thing"), Field: []*descriptor.FieldDescriptorProto{field}}},
+		SourceCodeInfo: &descriptor.SourceCodeInfo{
+			Location: []*descriptor.SourceCodeInfo_Location{
+				{Path: []int32{4, 0, 2, 0}, LeadingComments: proto.String(` The payload, e.g. {"@type": "type.googleapis.com/test.Thing"}` + "\n")},
+			},
+		},
+	})
+	payload := findField("payload", findMessage("Thing", tmpl.Files[0]))
+	require.Empty(t, payload.DisplayType)
+	require.Contains(t, payload.Description, `{"@type": "type.googleapis.com/test.Thing"}`)
+}
+
+func TestDeadlineAndRetryableDirectives(t *testing.T) {
+	directive := &Directive{Descrition: "Uploads a file.\n@deadline 1m30s\n@retryable"}
+	require.Equal(t, "1m30s", directive.Deadline())
+	require.True(t, directive.Retryable())
+	require.Equal(t, "Uploads a file.\n\n", directive.Descrition)
+
+	directive = &Directive{Descrition: "@deadline soon"}
+	require.Empty(t, directive.Deadline())
+	require.False(t, directive.Retryable())
+	require.Empty(t, directive.Descrition)
+
+	directive = &Directive{Descrition: "@deadline 30s per attempt"}
+	require.Equal(t, "30s", directive.Deadline())
+	require.Equal(t, " per attempt", directive.Descrition)
+
+	directive = &Directive{Descrition: "Past @deadlines 1m."}
+	require.Empty(t, directive.Deadline())
+	require.Equal(t, "Past @deadlines 1m.", directive.Descrition)
+
+	directive = &Directive{Descrition: "@retryable_errors UNAVAILABLE"}
+	require.False(t, directive.Retryable())
+	require.Equal(t, "@retryable_errors UNAVAILABLE", directive.Descrition)
+}
+
+func TestServiceMethodStreamNotes(t *testing.T) {
+	service := findService("VehicleService", vehicleFile)
+
+	method := findServiceMethod("AddModels", service)
+	require.Equal(t, "stream of Model", method.RequestStreamNote)
+	require.Equal(t, "stream of Model", method.ResponseStreamNote)
+
+	method = findServiceMethod("GetModels", service)
+	require.Empty(t, method.RequestStreamNote)
+	require.Equal(t, "stream of Model", method.ResponseStreamNote)
+
+	method = findServiceMethod("GetVehicle", service)
+	require.Empty(t, method.RequestStreamNote)
+	require.Empty(t, method.ResponseStreamNote)
+}
+
+func TestServiceMethodIdempotencyLevel(t *testing.T) {
+	method := findServiceMethod("GetVehicle", findService("VehicleService", vehicleFile))
+	require.Equal(t, IdempotencyUnknown, method.IdempotencyLevel)
+
+	tmpl := newTestTemplate(&descriptor.FileDescriptorProto{
+		Name:        proto.String("idempotency.proto"),
+		Package:     proto.String("test"),
+		MessageType: []*descriptor.DescriptorProto{{Name: proto.String("Thing")}},
+		Service: []*descriptor.ServiceDescriptorProto{{
+			Name: proto.String("ThingService"),
+			Method: []*descriptor.MethodDescriptorProto{{
+				Name:       proto.String("GetThing"),
+				InputType:  proto.String(".test.Thing"),
+				OutputType: proto.String(".test.Thing"),
+				Options:    &descriptor.MethodOptions{IdempotencyLevel: descriptor.MethodOptions_NO_SIDE_EFFECTS.Enum()},
+			}},
+		}},
+	})
+
+	method = findServiceMethod("GetThing", findService("ThingService", tmpl.Files[0]))
+	require.Equal(t, IdempotencyNoSideEffects, method.IdempotencyLevel)
+	require.Equal(t, "NO_SIDE_EFFECTS", method.Option("idempotency_level"))
+}
+
+func TestServiceMethodJSON(t *testing.T) {
+	method := func(name string, client, server bool, level descriptor.MethodOptions_IdempotencyLevel) *descriptor.MethodDescriptorProto {
+		return &descriptor.MethodDescriptorProto{
+			Name:            proto.String(name),
+			InputType:       proto.String(".test.Thing"),
+			OutputType:      proto.String(".test.Thing"),
+			ClientStreaming: proto.Bool(client),
+			ServerStreaming: proto.Bool(server),
+			Options:         &descriptor.MethodOptions{IdempotencyLevel: level.Enum()},
+		}
+	}
+
+	service := findService("ThingService", newTestTemplate(&descriptor.FileDescriptorProto{
+		Name:        proto.String("methods.proto"),
+		Package:     proto.String("test"),
+		MessageType: []*descriptor.DescriptorProto{{Name: proto.String("Thing")}},
+		Service: []*descriptor.ServiceDescriptorProto{{
+			Name: proto.String("ThingService"),
+			Method: []*descriptor.MethodDescriptorProto{
+				method("Get", false, false, descriptor.MethodOptions_NO_SIDE_EFFECTS),
+				method("Upload", true, false, descriptor.MethodOptions_IDEMPOTENT),
+				method("Watch", false, true, descriptor.MethodOptions_IDEMPOTENCY_UNKNOWN),
+				method("Chat", true, true, descriptor.MethodOptions_IDEMPOTENCY_UNKNOWN),
+			},
+		}},
+	}).Files[0])
+
+	expected := map[string][]interface{}{
+		"Get":    {"/test.ThingService/Get", StreamingKindUnary, true, true},
+		"Upload": {"/test.ThingService/Upload", StreamingKindClient, true, false},
+		"Watch":  {"/test.ThingService/Watch", StreamingKindServer, false, false},
+		"Chat":   {"/test.ThingService/Chat", StreamingKindBidi, false, false},
+	}
+
+	for name, values := range expected {
+		data, err := json.Marshal(findServiceMethod(name, service))
+		require.NoError(t, err)
+
+		var decoded map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		require.Equal(t, values, []interface{}{
+			decoded["fullMethodPath"],
+			decoded["streamingKind"],
+			decoded["idempotent"],
+			decoded["safe"],
+		}, name)
+	}
+}
+
+func TestDuplicateDirectives(t *testing.T) {
+	directive := &Directive{Descrition: "@title First\nGets a thing.\n@action get\n@version v1\n@title Second\n@action list\n@version v2"}
+	require.Equal(t, "First", directive.Title())
+	require.Equal(t, "get", directive.Action())
+	require.Equal(t, "v1", directive.Version())
+	require.Equal(t, "\nGets a thing.\n\n\n\n\n", directive.Descrition)
+
+	directive = &Directive{Descrition: "@title Same\n@title Same"}
+	require.Equal(t, "Same", directive.Title())
+	require.Equal(t, "\n", directive.Descrition)
+}
+
+func TestStatusDirective(t *testing.T) {
+	directive := &Directive{Descrition: "Creates a vehicle.\n@status 201"}
+	require.Equal(t, 201, directive.Status())
+	require.Equal(t, "Creates a vehicle.\n", directive.Descrition)
+
+	directive = &Directive{Descrition: "@status created"}
+	require.Zero(t, directive.Status())
+	require.Empty(t, directive.Descrition)
+
+	directive = &Directive{Descrition: "@status 42"}
+	require.Zero(t, directive.Status())
+
+	directive = &Directive{Descrition: "@status 204 when nothing changed"}
+	require.Equal(t, 204, directive.Status())
+	require.Equal(t, " when nothing changed", directive.Descrition)
+
+	directive = &Directive{Descrition: "The @statuses 200 and 201."}
+	require.Zero(t, directive.Status())
+	require.Equal(t, "The @statuses 200 and 201.", directive.Descrition)
+}
+
+func TestStabilityDirective(t *testing.T) {
+	directive := &Directive{Descrition: "Lists things.\n@beta"}
+	require.Equal(t, "beta", directive.Stability())
+	require.Equal(t, "Lists things.\n", directive.Descrition)
+
+	directive = &Directive{Descrition: "@alpha Lists things. @stable"}
+	require.Equal(t, "stable", directive.Stability())
+	require.Equal(t, " Lists things. ", directive.Descrition)
+
+	directive = &Directive{Descrition: "Uses @alphabet soup."}
+	require.Empty(t, directive.Stability())
+	require.Equal(t, "Uses @alphabet soup.", directive.Descrition)
+
+	require.Empty(t, findMessage("Vehicle", vehicleFile).Stability)
+	require.Empty(t, findService("VehicleService", vehicleFile).Stability)
+}
+
 func TestJsonIndex(t *testing.T) {
 	actual := `{"args": {},"headers": {"Accept": "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,image/apng,*/*;q=0.8","Accept-Encoding": "gzip, deflate","Accept-Language": "zh-CN,zh;q=0.9","Connection": "close","Host": "httpbin.org","Upgrade-Insecure-Requests": "1","User-Agent": "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_13_6) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/69.0.3497.100 Safari/537.36"},"origin": "103.*.*.*","url": "http://httpbin.org/get"}`
 
@@ -545,6 +1254,1166 @@ func TestRegex(t *testing.T) {
 	fmt.Println(result1)
 }
 
+func TestOrderDirective(t *testing.T) {
+	directive := &Directive{Descrition: "A vehicle.\n@order 2"}
+	require.Equal(t, 2, directive.Order())
+	require.Equal(t, "A vehicle.\n", directive.Descrition)
+
+	directive = &Directive{Descrition: "@order first"}
+	require.Zero(t, directive.Order())
+	require.Empty(t, directive.Descrition)
+
+	directive = &Directive{Descrition: "@order 3 (after the totals)"}
+	require.Equal(t, 3, directive.Order())
+	require.Equal(t, " (after the totals)", directive.Descrition)
+
+	directive = &Directive{Descrition: "See @ordered 5 times."}
+	require.Zero(t, directive.Order())
+	require.Equal(t, "See @ordered 5 times.", directive.Descrition)
+}
+
+func TestOrderedElements(t *testing.T) {
+	file := &File{
+		Messages: []*Message{
+			{LongName: "Alpha"},
+			{LongName: "Beta", Order: 2},
+			{LongName: "Delta"},
+			{LongName: "Gamma", Order: 1},
+		},
+		Services: []*Service{{LongName: "Alpha"}, {LongName: "Beta", Order: 1}},
+		Enums:    []*Enum{{LongName: "Alpha", Order: 3}, {LongName: "Beta", Order: 3}},
+	}
+
+	var names []string
+	for _, m := range file.OrderedMessages() {
+		names = append(names, m.LongName)
+	}
+	require.Equal(t, []string{"Gamma", "Beta", "Alpha", "Delta"}, names)
+	require.Equal(t, "Alpha", file.Messages[0].LongName)
+
+	require.Equal(t, "Beta", file.OrderedServices()[0].LongName)
+	require.Equal(t, "Alpha", file.OrderedEnums()[0].LongName)
+}
+
+func TestIDDirective(t *testing.T) {
+	directive := &Directive{Descrition: "The resource name. @id"}
+	require.True(t, directive.ID())
+	require.Equal(t, "The resource name. ", directive.Descrition)
+
+	directive = &Directive{Descrition: "Safe to retry. @idempotent"}
+	require.False(t, directive.ID())
+	require.Equal(t, "Safe to retry. @idempotent", directive.Descrition)
+}
+
+func TestIdentifierField(t *testing.T) {
+	file := newTestTemplate(&descriptor.FileDescriptorProto{
+		Name:    proto.String("id.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptor.DescriptorProto{
+			{
+				Name: proto.String("Book"),
+				Field: []*descriptor.FieldDescriptorProto{
+					newTestField("title", 1, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+					newTestField("name", 2, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+					newTestField("isbn", 3, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+				},
+			},
+		},
+		SourceCodeInfo: &descriptor.SourceCodeInfo{
+			Location: []*descriptor.SourceCodeInfo_Location{
+				{Path: []int32{4, 0, 2, 1}, LeadingComments: proto.String(" The resource name.\n @id\n")},
+				{Path: []int32{4, 0, 2, 2}, LeadingComments: proto.String(" @id\n")},
+			},
+		},
+	}).Files[0]
+
+	book := findMessage("Book", file)
+	require.False(t, findField("title", book).IsIdentifier)
+	require.True(t, findField("name", book).IsIdentifier)
+	require.Equal(t, "The resource name.\n", findField("name", book).Description)
+	require.True(t, findField("name", book) == book.IdentifierField())
+
+	require.Nil(t, findMessage("Vehicle", vehicleFile).IdentifierField())
+}
+
+func TestExhaustiveOneof(t *testing.T) {
+	file := newTestTemplate(&descriptor.FileDescriptorProto{
+		Name:    proto.String("oneof.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptor.DescriptorProto{
+			{
+				Name: proto.String("Payment"),
+				Field: []*descriptor.FieldDescriptorProto{
+					{Name: proto.String("card"), Number: proto.Int32(1), Type: descriptor.FieldDescriptorProto_TYPE_STRING.Enum(), OneofIndex: proto.Int32(0)},
+					{Name: proto.String("iban"), Number: proto.Int32(2), Type: descriptor.FieldDescriptorProto_TYPE_STRING.Enum(), OneofIndex: proto.Int32(0)},
+					{Name: proto.String("note"), Number: proto.Int32(3), Type: descriptor.FieldDescriptorProto_TYPE_STRING.Enum(), OneofIndex: proto.Int32(1)},
+				},
+				OneofDecl: []*descriptor.OneofDescriptorProto{{Name: proto.String("method")}, {Name: proto.String("extra")}},
+			},
+		},
+		SourceCodeInfo: &descriptor.SourceCodeInfo{
+			Location: []*descriptor.SourceCodeInfo_Location{
+				{Path: []int32{4, 0, 8, 0}, LeadingComments: proto.String(" How to pay. @exhaustive\n")},
+			},
+		},
+	}).Files[0]
+
+	payment := findMessage("Payment", file)
+	require.True(t, payment.Oneofs[0].Exhaustive)
+	require.Equal(t, "How to pay. ", payment.Oneofs[0].Description)
+	require.False(t, payment.Oneofs[1].Exhaustive)
+	require.False(t, findMessage("Vehicle", vehicleFile).Oneofs[0].Exhaustive)
+
+	directive := &Directive{Descrition: "See @exhaustiveness."}
+	require.False(t, directive.Exhaustive())
+	require.Equal(t, "See @exhaustiveness.", directive.Descrition)
+}
+
+func TestTypeNamesRelativeToPackage(t *testing.T) {
+	fieldTypes := func(pkg string) (*Message, *ServiceMethod) {
+		prefix := ""
+		if pkg != "" {
+			prefix = "." + pkg
+		}
+
+		file := newTestTemplate(&descriptor.FileDescriptorProto{
+			Name:    proto.String("types.proto"),
+			Package: proto.String(pkg),
+			Syntax:  proto.String("proto3"),
+			MessageType: []*descriptor.DescriptorProto{
+				{
+					Name: proto.String("Thing"),
+					Field: []*descriptor.FieldDescriptorProto{
+						newTestField("same", 1, descriptor.FieldDescriptorProto_TYPE_MESSAGE, prefix+".Thing"),
+						newTestField("sub", 2, descriptor.FieldDescriptorProto_TYPE_MESSAGE, prefix+".v1.Part"),
+						newTestField("foreign", 3, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.Empty"),
+					},
+				},
+			},
+			Service: []*descriptor.ServiceDescriptorProto{
+				{
+					Name: proto.String("ThingService"),
+					Method: []*descriptor.MethodDescriptorProto{
+						{Name: proto.String("Get"), InputType: proto.String(prefix + ".Thing"), OutputType: proto.String(".google.protobuf.Empty")},
+					},
+				},
+			},
+		}).Files[0]
+
+		return findMessage("Thing", file), findServiceMethod("Get", findService("ThingService", file))
+	}
+
+	for _, pkg := range []string{"com.example", ""} {
+		msg, method := fieldTypes(pkg)
+		full := strings.TrimPrefix(pkg+".", ".")
+
+		require.Equal(t, "Thing", findField("same", msg).LongType)
+		require.Equal(t, full+"Thing", findField("same", msg).FullType)
+		require.Equal(t, "v1.Part", findField("sub", msg).LongType)
+		require.Equal(t, "google.protobuf.Empty", findField("foreign", msg).LongType)
+
+		require.Equal(t, "Thing", method.RequestLongType)
+		require.Equal(t, full+"Thing", method.RequestFullType)
+		require.Equal(t, "google.protobuf.Empty", method.ResponseLongType)
+		require.Equal(t, "google.protobuf.Empty", method.ResponseFullType)
+	}
+}
+
+func TestProto3OptionalLabels(t *testing.T) {
+	cookie := findMessage("Cookie", cookieFile)
+	require.False(t, cookie.HasOneofs)
+
+	name := findField("name", cookie)
+	require.Equal(t, "optional", name.Label)
+	require.False(t, name.IsOneof)
+	require.Empty(t, name.OneofDecl)
+
+	implicit := newTestField("implicit", 1, descriptor.FieldDescriptorProto_TYPE_STRING, "")
+	implicit.Label = nil
+
+	explicit := newTestField("explicit", 2, descriptor.FieldDescriptorProto_TYPE_STRING, "")
+	explicit.Label = nil
+	explicit.Proto3Optional = proto.Bool(true)
+	explicit.OneofIndex = proto.Int32(1)
+
+	member := newTestField("member", 3, descriptor.FieldDescriptorProto_TYPE_STRING, "")
+	member.OneofIndex = proto.Int32(0)
+
+	repeated := newTestField("repeated", 4, descriptor.FieldDescriptorProto_TYPE_STRING, "")
+	repeated.Label = descriptor.FieldDescriptorProto_LABEL_REPEATED.Enum()
+
+	file := newTestTemplate(&descriptor.FileDescriptorProto{
+		Name:    proto.String("labels.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptor.DescriptorProto{
+			{
+				Name:      proto.String("Thing"),
+				Field:     []*descriptor.FieldDescriptorProto{implicit, explicit, member, repeated},
+				OneofDecl: []*descriptor.OneofDescriptorProto{{Name: proto.String("choice")}, {Name: proto.String("_explicit")}},
+			},
+		},
+	}).Files[0]
+
+	thing := findMessage("Thing", file)
+	require.True(t, thing.HasOneofs)
+	require.Equal(t, "", findField("implicit", thing).Label)
+	require.Equal(t, "optional", findField("explicit", thing).Label)
+	require.False(t, findField("explicit", thing).IsOneof)
+	require.Equal(t, "", findField("member", thing).Label)
+	require.True(t, findField("member", thing).IsOneof)
+	require.Equal(t, "repeated", findField("repeated", thing).Label)
+}
+
+func TestOneofIndex(t *testing.T) {
+	plain := newTestField("plain", 1, descriptor.FieldDescriptorProto_TYPE_STRING, "")
+
+	first := newTestField("first", 2, descriptor.FieldDescriptorProto_TYPE_STRING, "")
+	first.OneofIndex = proto.Int32(0)
+
+	explicit := newTestField("explicit", 3, descriptor.FieldDescriptorProto_TYPE_STRING, "")
+	explicit.Proto3Optional = proto.Bool(true)
+	explicit.OneofIndex = proto.Int32(1)
+
+	second := newTestField("second", 4, descriptor.FieldDescriptorProto_TYPE_INT32, "")
+	second.OneofIndex = proto.Int32(0)
+
+	thing := findMessage("Thing", newTestTemplate(&descriptor.FileDescriptorProto{
+		Name:    proto.String("oneofs.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptor.DescriptorProto{{
+			Name:      proto.String("Thing"),
+			Field:     []*descriptor.FieldDescriptorProto{plain, first, explicit, second},
+			OneofDecl: []*descriptor.OneofDescriptorProto{{Name: proto.String("choice")}, {Name: proto.String("_explicit")}},
+		}},
+	}).Files[0])
+
+	require.Nil(t, findField("plain", thing).OneofIndex)
+	require.False(t, findField("plain", thing).IsSyntheticOneof)
+
+	for name, index := range map[string]int{"first": 0, "explicit": 1, "second": 0} {
+		require.NotNil(t, findField(name, thing).OneofIndex, name)
+		require.Equal(t, index, *findField(name, thing).OneofIndex, name)
+	}
+
+	require.True(t, findField("explicit", thing).IsSyntheticOneof)
+	require.False(t, findField("first", thing).IsSyntheticOneof)
+}
+
+func TestCrossPackageMethods(t *testing.T) {
+	method := func(name, in, out string) *descriptor.MethodDescriptorProto {
+		return &descriptor.MethodDescriptorProto{Name: proto.String(name), InputType: proto.String(in), OutputType: proto.String(out)}
+	}
+
+	tmpl := newTestTemplate(
+		&descriptor.FileDescriptorProto{
+			Name:        proto.String("types.proto"),
+			Package:     proto.String("com.example.types"),
+			Syntax:      proto.String("proto3"),
+			MessageType: []*descriptor.DescriptorProto{{Name: proto.String("lowercase")}},
+		},
+		&descriptor.FileDescriptorProto{
+			Name:    proto.String("service.proto"),
+			Package: proto.String("com.example"),
+			Syntax:  proto.String("proto3"),
+			MessageType: []*descriptor.DescriptorProto{
+				{Name: proto.String("Thing"), NestedType: []*descriptor.DescriptorProto{{Name: proto.String("Part")}}},
+			},
+			Service: []*descriptor.ServiceDescriptorProto{
+				{
+					Name: proto.String("ThingService"),
+					Method: []*descriptor.MethodDescriptorProto{
+						method("Same", ".com.example.Thing", ".com.example.Thing.Part"),
+						method("Empty", ".com.example.Thing", ".google.protobuf.Empty"),
+						method("Types", ".com.example.types.lowercase", ".com.example.Thing"),
+						method("Bare", ".Bare", ".com.example.Thing"),
+					},
+				},
+			},
+		},
+	)
+
+	service := findService("ThingService", tmpl.Files[1])
+	require.False(t, findServiceMethod("Same", service).CrossPackage)
+	require.True(t, findServiceMethod("Empty", service).CrossPackage)
+	require.True(t, findServiceMethod("Types", service).CrossPackage)
+
+	same := findServiceMethod("Same", service)
+	require.Equal(t, "com.example", same.RequestPackage)
+	require.Equal(t, "com.example", same.ResponsePackage)
+	require.Equal(t, "google.protobuf", findServiceMethod("Empty", service).ResponsePackage)
+	require.Equal(t, "com.example.types", findServiceMethod("Types", service).RequestPackage)
+	require.Empty(t, findServiceMethod("Bare", service).RequestPackage)
+
+	require.False(t, findServiceMethod("BookVehicle", findService("BookingService", bookingFile)).CrossPackage)
+}
+
+func TestDetachedComments(t *testing.T) {
+	file := newTestTemplate(&descriptor.FileDescriptorProto{
+		Name:        proto.String("detached.proto"),
+		Package:     proto.String("test"),
+		Syntax:      proto.String("proto3"),
+		MessageType: []*descriptor.DescriptorProto{{Name: proto.String("First")}, {Name: proto.String("Second")}},
+		Service:     []*descriptor.ServiceDescriptorProto{{Name: proto.String("Things")}},
+		SourceCodeInfo: &descriptor.SourceCodeInfo{
+			Location: []*descriptor.SourceCodeInfo_Location{
+				{Path: []int32{12}, LeadingDetachedComments: []string{" Copyright.\n"}, LeadingComments: proto.String(" Things.\n")},
+				{Path: []int32{2}, LeadingDetachedComments: []string{" Package notes.\n"}},
+				{
+					Path:                    []int32{4, 0},
+					LeadingDetachedComments: []string{" ==== Models ====\n", " More models.\n"},
+					LeadingComments:         proto.String(" The first.\n"),
+				},
+				{Path: []int32{6, 0}, LeadingDetachedComments: []string{" ==== Services ====\n"}},
+			},
+		},
+	}).Files[0]
+
+	require.Equal(t, []string{"Copyright.", "Package notes."}, file.DetachedComments)
+	require.Equal(t, "Things.", file.Description)
+
+	first := findMessage("First", file)
+	require.Equal(t, []string{"==== Models ====", "More models."}, first.DetachedComments)
+	require.Equal(t, "The first.", first.Description)
+
+	require.NotNil(t, findMessage("Second", file).DetachedComments)
+	require.Empty(t, findMessage("Second", file).DetachedComments)
+	require.Equal(t, []string{"==== Services ===="}, findService("Things", file).DetachedComments)
+}
+
+func TestFlagsEnum(t *testing.T) {
+	value := func(name string, number int32) *descriptor.EnumValueDescriptorProto {
+		return &descriptor.EnumValueDescriptorProto{Name: proto.String(name), Number: proto.Int32(number)}
+	}
+
+	file := newTestTemplate(&descriptor.FileDescriptorProto{
+		Name:    proto.String("flags.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		EnumType: []*descriptor.EnumDescriptorProto{
+			{
+				Name: proto.String("Permission"),
+				Value: []*descriptor.EnumValueDescriptorProto{
+					value("NONE", 0), value("READ", 1), value("WRITE", 2), value("EXECUTE", 8), value("READ_WRITE", 3),
+				},
+			},
+			{Name: proto.String("Plain"), Value: []*descriptor.EnumValueDescriptorProto{value("ZERO", 0), value("FOUR", 4)}},
+		},
+		SourceCodeInfo: &descriptor.SourceCodeInfo{
+			Location: []*descriptor.SourceCodeInfo_Location{
+				{Path: []int32{5, 0}, LeadingComments: proto.String(" Access rights. @flags\n")},
+			},
+		},
+	}).Files[0]
+
+	permission := findEnum("Permission", file)
+	require.True(t, permission.IsFlags)
+	require.Equal(t, "Access rights. ", permission.Description)
+
+	var positions []int
+	for _, v := range permission.Values {
+		positions = append(positions, v.BitPosition)
+	}
+	require.Equal(t, []int{-1, 0, 1, 3, -1}, positions)
+
+	plain := findEnum("Plain", file)
+	require.False(t, plain.IsFlags)
+	require.Zero(t, plain.Values[1].BitPosition)
+
+	directive := &Directive{Descrition: "See @flagship."}
+	require.False(t, directive.Flags())
+	require.Equal(t, "See @flagship.", directive.Descrition)
+}
+
+func TestByPackage(t *testing.T) {
+	message := func(name string) *descriptor.DescriptorProto {
+		return &descriptor.DescriptorProto{Name: proto.String(name)}
+	}
+
+	tmpl := newTestTemplate(
+		&descriptor.FileDescriptorProto{
+			Name:        proto.String("b.proto"),
+			Package:     proto.String("com.example"),
+			Syntax:      proto.String("proto3"),
+			MessageType: []*descriptor.DescriptorProto{message("Zebra"), message("Apple")},
+		},
+		&descriptor.FileDescriptorProto{
+			Name:        proto.String("other.proto"),
+			Package:     proto.String("com.acme"),
+			Syntax:      proto.String("proto3"),
+			MessageType: []*descriptor.DescriptorProto{message("Widget")},
+		},
+		&descriptor.FileDescriptorProto{
+			Name:        proto.String("a.proto"),
+			Package:     proto.String("com.example"),
+			Syntax:      proto.String("proto3"),
+			MessageType: []*descriptor.DescriptorProto{message("Mango")},
+			EnumType:    []*descriptor.EnumDescriptorProto{{Name: proto.String("Color")}},
+		},
+	)
+
+	packages := tmpl.ByPackage()
+	require.Len(t, packages, 2)
+
+	require.Equal(t, "com.acme", packages[0].Package)
+	require.Equal(t, []string{"other.proto"}, packages[0].Files)
+	require.Len(t, packages[0].Messages, 1)
+
+	example := packages[1]
+	require.Equal(t, "com.example", example.Package)
+	require.Equal(t, []string{"b.proto", "a.proto"}, example.Files)
+
+	var names []string
+	for _, m := range example.Messages {
+		names = append(names, m.LongName)
+	}
+	require.Equal(t, []string{"Apple", "Mango", "Zebra"}, names)
+	require.Len(t, example.Enums, 1)
+	require.Empty(t, example.Services)
+
+	// the files themselves are left untouched
+	require.Equal(t, "Apple", tmpl.Files[0].Messages[0].LongName)
+	require.Len(t, tmpl.Files[0].Messages, 2)
+}
+
+func TestNoSchemaDirective(t *testing.T) {
+	directive := &Directive{Descrition: "Internal etag. @no_schema"}
+	require.True(t, directive.NoSchema())
+	require.Equal(t, "Internal etag. ", directive.Descrition)
+
+	directive = &Directive{Descrition: "See @no_schemas."}
+	require.False(t, directive.NoSchema())
+	require.Equal(t, "See @no_schemas.", directive.Descrition)
+
+	require.False(t, findField("id", findMessage("Vehicle", vehicleFile)).NoSchema)
+}
+
+func TestDynamicJSONFields(t *testing.T) {
+	fd := &descriptor.FileDescriptorProto{
+		Name:    proto.String("dynamic.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptor.DescriptorProto{
+			{
+				Name: proto.String("Event"),
+				Field: []*descriptor.FieldDescriptorProto{
+					newTestField("payload", 1, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.Struct"),
+					newTestField("value", 2, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.Value"),
+					newTestField("items", 3, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.ListValue"),
+					newTestField("at", 4, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.Timestamp"),
+				},
+			},
+		},
+	}
+
+	event := findMessage("Event", newTestTemplate(fd).Files[0])
+	require.True(t, findField("payload", event).IsDynamicJSON)
+	require.Equal(t, "google.protobuf.Struct", findField("payload", event).LongType)
+	require.False(t, findField("at", event).IsDynamicJSON)
+
+	tmpl := newTestTemplateWithOptions(TemplateOptions{DynamicJSONTypes: true}, fd)
+
+	event = findMessage("Event", tmpl.Files[0])
+	require.Equal(t, "json object", findField("payload", event).LongType)
+	require.Equal(t, "google.protobuf.Struct", findField("payload", event).FullType)
+	require.Equal(t, "json value", findField("value", event).LongType)
+	require.Equal(t, "json array", findField("items", event).LongType)
+	require.Equal(t, "google.protobuf.Timestamp", findField("at", event).LongType)
+}
+
+func TestExternalTypeFields(t *testing.T) {
+	tmpl := newTestTemplate(
+		&descriptor.FileDescriptorProto{
+			Name:        proto.String("common.proto"),
+			Package:     proto.String("test"),
+			Syntax:      proto.String("proto3"),
+			MessageType: []*descriptor.DescriptorProto{{Name: proto.String("Money")}},
+			EnumType:    []*descriptor.EnumDescriptorProto{{Name: proto.String("Currency")}},
+		},
+		&descriptor.FileDescriptorProto{
+			Name:    proto.String("order.proto"),
+			Package: proto.String("test"),
+			Syntax:  proto.String("proto3"),
+			MessageType: []*descriptor.DescriptorProto{
+				{
+					Name: proto.String("Order"),
+					Field: []*descriptor.FieldDescriptorProto{
+						newTestField("id", 1, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+						newTestField("total", 2, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".test.Money"),
+						newTestField("currency", 3, descriptor.FieldDescriptorProto_TYPE_ENUM, ".test.Currency"),
+						newTestField("item", 4, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".test.Item"),
+						newTestField("created_at", 5, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.Timestamp"),
+					},
+				},
+				{Name: proto.String("Item")},
+			},
+		},
+	)
+
+	order := findMessage("Order", tmpl.Files[1])
+	require.False(t, findField("id", order).IsExternalType)
+	require.True(t, findField("total", order).IsExternalType)
+	require.True(t, findField("currency", order).IsExternalType)
+	require.False(t, findField("item", order).IsExternalType)
+	require.True(t, findField("created_at", order).IsExternalType)
+}
+
+func TestDocsDirective(t *testing.T) {
+	directive := &Directive{Descrition: "A booking. @docs https://example.com/booking\n@docs https://example.com/faq"}
+	require.Equal(t, "https://example.com/booking", directive.DocsURL())
+	require.Equal(t, []string{"https://example.com/booking", "https://example.com/faq"}, directive.Docs())
+	require.Equal(t, "A booking. \n", directive.Descrition)
+
+	vehicle := findMessage("Vehicle", vehicleFile)
+	require.Empty(t, vehicle.DocsURL)
+	require.Empty(t, vehicle.DocsURLs)
+	require.Empty(t, findField("id", vehicle).DocsURL)
+}
+
+func TestMaxSizeDirective(t *testing.T) {
+	for comment, size := range map[string]string{
+		"A blob. @max_size 1MB":   "1MB",
+		"@max_size 512 KiB":       "512 KiB",
+		"@max_size 4096":          "4096",
+		"@max_size 1.5gb":         "1.5gb",
+		"@max_size a lot":         "",
+		"@max_size":               "",
+		"No limits. @status 200 ": "",
+	} {
+		directive := &Directive{Descrition: comment}
+		require.Equal(t, size, directive.MaxSize(), comment)
+		require.NotContains(t, directive.Descrition, "@max_size", comment)
+	}
+
+	directive := &Directive{Descrition: "See @max_sizes 1MB."}
+	require.Empty(t, directive.MaxSize())
+	require.Equal(t, "See @max_sizes 1MB.", directive.Descrition)
+
+	tmpl := newTestTemplate(&descriptor.FileDescriptorProto{
+		Name:        proto.String("sizes.proto"),
+		Package:     proto.String("test"),
+		MessageType: []*descriptor.DescriptorProto{{Name: proto.String("Upload")}},
+		SourceCodeInfo: &descriptor.SourceCodeInfo{
+			Location: []*descriptor.SourceCodeInfo_Location{
+				{Path: []int32{4, 0}, LeadingComments: proto.String(" An upload.\n @max_size 10MB\n")},
+			},
+		},
+	})
+
+	upload := findMessage("Upload", tmpl.Files[0])
+	require.Equal(t, "10MB", upload.MaxSize)
+	require.Equal(t, "An upload.\n", upload.Description)
+	require.Empty(t, findMessage("Vehicle", vehicleFile).MaxSize)
+}
+
+func TestDeprecatedDirective(t *testing.T) {
+	directive := &Directive{Descrition: "An old booking. @deprecated -> NewBooking"}
+	require.True(t, directive.Deprecated())
+	require.Equal(t, "NewBooking", directive.ReplacedBy())
+	require.Equal(t, "An old booking. ", directive.Descrition)
+
+	directive = &Directive{Descrition: "@deprecated Don't use this."}
+	require.True(t, directive.Deprecated())
+	require.Empty(t, directive.ReplacedBy())
+	require.Equal(t, " Don't use this.", directive.Descrition)
+
+	fd := &descriptor.FileDescriptorProto{
+		Name:    proto.String("deprecated.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptor.DescriptorProto{
+			{Name: proto.String("OldThing")},
+			{Name: proto.String("NewThing")},
+			{Name: proto.String("Other")},
+		},
+		SourceCodeInfo: &descriptor.SourceCodeInfo{
+			Location: []*descriptor.SourceCodeInfo_Location{
+				{Path: []int32{4, 0}, LeadingComments: proto.String("@deprecated -> NewThing")},
+				{Path: []int32{4, 2}, LeadingComments: proto.String("@deprecated -> Missing")},
+			},
+		},
+	}
+
+	file := newTestTemplate(fd).Files[0]
+	old := findMessage("OldThing", file)
+	require.Equal(t, "NewThing", old.ReplacedBy)
+	require.Equal(t, findMessage("NewThing", file).Anchor(), old.ReplacedByAnchor)
+	require.Equal(t, true, old.Options["deprecated"])
+
+	other := findMessage("Other", file)
+	require.Equal(t, "Missing", other.ReplacedBy)
+	require.Empty(t, other.ReplacedByAnchor)
+	require.Empty(t, findMessage("NewThing", file).ReplacedBy)
+}
+
+func TestVisibleMethodCount(t *testing.T) {
+	service := &Service{Methods: []*ServiceMethod{{Name: "Get"}, {Name: "Debug", Exclude: true}, {Name: "List"}}}
+	require.Equal(t, 2, service.VisibleMethodCount())
+	require.Equal(t, 0, new(Service).VisibleMethodCount())
+}
+
+func TestNameParts(t *testing.T) {
+	fd := &descriptor.FileDescriptorProto{
+		Name:    proto.String("nested.proto"),
+		Package: proto.String("com.example"),
+		MessageType: []*descriptor.DescriptorProto{{
+			Name: proto.String("Outer"),
+			NestedType: []*descriptor.DescriptorProto{{
+				Name:       proto.String("Inner"),
+				NestedType: []*descriptor.DescriptorProto{{Name: proto.String("Leaf")}},
+				EnumType:   []*descriptor.EnumDescriptorProto{{Name: proto.String("Kind")}},
+			}},
+		}},
+	}
+
+	file := newTestTemplate(fd).Files[0]
+	require.Equal(t, []string{"Outer"}, findMessage("Outer", file).NameParts())
+	require.Equal(t, []string{"Outer", "Inner", "Leaf"}, findMessage("Outer.Inner.Leaf", file).NameParts())
+	require.Equal(t, []string{"Outer", "Inner", "Kind"}, findEnum("Outer.Inner.Kind", file).NameParts())
+	require.Equal(t, []string{"Thing"}, Message{Name: "Thing"}.NameParts())
+}
+
+func TestSingleFieldWrappers(t *testing.T) {
+	items := newTestField("items", 1, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".test.Item")
+	items.Label = descriptor.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	choice := newTestField("choice", 1, descriptor.FieldDescriptorProto_TYPE_STRING, "")
+	choice.OneofIndex = proto.Int32(0)
+
+	fd := &descriptor.FileDescriptorProto{
+		Name:    proto.String("wrappers.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptor.DescriptorProto{
+			{Name: proto.String("ListItemsResponse"), Field: []*descriptor.FieldDescriptorProto{items}},
+			{
+				Name: proto.String("Item"),
+				Field: []*descriptor.FieldDescriptorProto{
+					newTestField("id", 1, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+					newTestField("name", 2, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+				},
+			},
+			{
+				Name:      proto.String("Choice"),
+				Field:     []*descriptor.FieldDescriptorProto{choice},
+				OneofDecl: []*descriptor.OneofDescriptorProto{{Name: proto.String("value")}},
+			},
+			{Name: proto.String("Empty")},
+		},
+	}
+
+	file := newTestTemplate(fd).Files[0]
+	wrapper := findMessage("ListItemsResponse", file)
+	require.True(t, wrapper.IsSingleFieldWrapper)
+	require.Equal(t, "items", wrapper.WrappedField().Name)
+
+	for _, name := range []string{"Item", "Choice", "Empty"} {
+		require.False(t, findMessage(name, file).IsSingleFieldWrapper, name)
+		require.Nil(t, findMessage(name, file).WrappedField(), name)
+	}
+}
+
+func TestMapValueFields(t *testing.T) {
+	repeated := descriptor.FieldDescriptorProto_LABEL_REPEATED
+	cars := newTestField("cars", 1, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".test.Garage.CarsEntry")
+	cars.Label = &repeated
+	names := newTestField("names", 2, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".test.Garage.NamesEntry")
+	names.Label = &repeated
+
+	fd := &descriptor.FileDescriptorProto{
+		Name:    proto.String("garage.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptor.DescriptorProto{
+			{
+				Name: proto.String("Car"),
+				Field: []*descriptor.FieldDescriptorProto{
+					newTestField("model", 1, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+					newTestField("engine", 2, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".test.Engine"),
+				},
+			},
+			{
+				Name:  proto.String("Engine"),
+				Field: []*descriptor.FieldDescriptorProto{newTestField("power", 1, descriptor.FieldDescriptorProto_TYPE_INT32, "")},
+			},
+			{
+				Name:  proto.String("Garage"),
+				Field: []*descriptor.FieldDescriptorProto{cars, names},
+				NestedType: []*descriptor.DescriptorProto{
+					newTestMapEntry("CarsEntry", newTestField("", 0, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".test.Car")),
+					newTestMapEntry("NamesEntry", newTestField("", 0, descriptor.FieldDescriptorProto_TYPE_STRING, "")),
+				},
+			},
+		},
+	}
+
+	garage := findMessage("Garage", newTestTemplate(fd).Files[0])
+	require.Nil(t, findField("cars", garage).MapValueFields)
+
+	tmpl := newTestTemplateWithOptions(TemplateOptions{MaxMapValueDepth: 1}, fd)
+
+	garage = findMessage("Garage", tmpl.Files[0])
+	fields := findField("cars", garage).MapValueFields
+	require.Len(t, fields, 2)
+	require.Equal(t, "model", fields[0].Name)
+	require.Equal(t, "engine", fields[1].Name)
+	require.True(t, fields[1].IsLink)
+	require.Empty(t, fields[1].Fields)
+	require.Nil(t, findField("names", garage).MapValueFields)
+
+	tmpl = newTestTemplateWithOptions(TemplateOptions{MaxMapValueDepth: 2}, fd)
+	fields = findField("cars", findMessage("Garage", tmpl.Files[0])).MapValueFields
+	require.False(t, fields[1].IsLink)
+	require.Equal(t, "power", fields[1].Fields[0].Name)
+}
+
+func TestMessagesNamedLikeMapEntries(t *testing.T) {
+	repeated := descriptor.FieldDescriptorProto_LABEL_REPEATED
+	entries := newTestField("entries", 1, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".test.LogEntry")
+	entries.Label = &repeated
+	lines := newTestField("lines", 2, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".test.Log.LineEntry")
+	lines.Label = &repeated
+	tags := newTestField("tags", 3, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".test.Log.TagsEntry")
+	tags.Label = &repeated
+
+	tmpl := newTestTemplate(&descriptor.FileDescriptorProto{
+		Name:    proto.String("log.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptor.DescriptorProto{
+			{Name: proto.String("LogEntry")},
+			{
+				Name:  proto.String("Log"),
+				Field: []*descriptor.FieldDescriptorProto{entries, lines, tags},
+				NestedType: []*descriptor.DescriptorProto{
+					{Name: proto.String("LineEntry")},
+					newTestMapEntry("TagsEntry", newTestField("", 0, descriptor.FieldDescriptorProto_TYPE_STRING, "")),
+				},
+			},
+		},
+	})
+
+	log := findMessage("Log", tmpl.Files[0])
+	for _, name := range []string{"entries", "lines"} {
+		field := findField(name, log)
+		require.False(t, field.IsMap, name)
+		require.Equal(t, "message", field.TypeKind, name)
+		require.Equal(t, "repeated", field.Label, name)
+	}
+
+	require.True(t, findField("tags", log).IsMap)
+	require.Equal(t, "map<string, string>", findField("tags", log).MapTypeString())
+}
+
+func TestEnumValueRanges(t *testing.T) {
+	value := func(name string, number int32) *descriptor.EnumValueDescriptorProto {
+		return &descriptor.EnumValueDescriptorProto{Name: proto.String(name), Number: proto.Int32(number)}
+	}
+
+	tmpl := newTestTemplate(&descriptor.FileDescriptorProto{
+		Name:    proto.String("errors.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		EnumType: []*descriptor.EnumDescriptorProto{{
+			Name: proto.String("ErrorCode"),
+			Value: []*descriptor.EnumValueDescriptorProto{
+				value("OK", 0),
+				value("NOT_FOUND", 1004),
+				value("BAD_REQUEST", 1000),
+				value("INTERNAL", 2000),
+			},
+		}},
+		SourceCodeInfo: &descriptor.SourceCodeInfo{
+			Location: []*descriptor.SourceCodeInfo_Location{{
+				Path:            []int32{5, 0},
+				LeadingComments: proto.String(" Error codes.\n @range 1000-1999 Client Errors\n @range 2000 - 2999 Server Errors\n"),
+			}},
+		},
+	})
+
+	enum := findEnum("ErrorCode", tmpl.Files[0])
+	require.Equal(t, "Error codes.\n\n", enum.Description)
+	require.Equal(t, []ValueRange{
+		{Start: 1000, End: 1999, Label: "Client Errors"},
+		{Start: 2000, End: 2999, Label: "Server Errors"},
+	}, enum.ValueRanges)
+
+	require.Equal(t, []*EnumValue{enum.Values[1], enum.Values[2]}, enum.ValuesInRange(enum.ValueRanges[0]))
+	require.Equal(t, []*EnumValue{enum.Values[3]}, enum.ValuesInRange(enum.ValueRanges[1]))
+	require.Empty(t, findEnum("BookingType", bookingFile).ValueRanges)
+}
+
+func TestDefaultEnumAnchor(t *testing.T) {
+	color := newTestField("color", 1, descriptor.FieldDescriptorProto_TYPE_ENUM, ".test.Color")
+	color.DefaultValue = proto.String("COLOR_RED")
+	unknown := newTestField("unknown", 2, descriptor.FieldDescriptorProto_TYPE_ENUM, ".test.Color")
+	unknown.DefaultValue = proto.String("COLOR_PURPLE")
+	name := newTestField("name", 3, descriptor.FieldDescriptorProto_TYPE_STRING, "")
+	name.DefaultValue = proto.String("COLOR_RED")
+
+	tmpl := newTestTemplate(&descriptor.FileDescriptorProto{
+		Name:    proto.String("defaults.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto2"),
+		MessageType: []*descriptor.DescriptorProto{{
+			Name:  proto.String("Paint"),
+			Field: []*descriptor.FieldDescriptorProto{color, unknown, name},
+		}},
+		EnumType: []*descriptor.EnumDescriptorProto{{
+			Name: proto.String("Color"),
+			Value: []*descriptor.EnumValueDescriptorProto{
+				{Name: proto.String("COLOR_UNSPECIFIED"), Number: proto.Int32(0)},
+				{Name: proto.String("COLOR_RED"), Number: proto.Int32(1)},
+			},
+		}},
+	})
+
+	paint := findMessage("Paint", tmpl.Files[0])
+	require.Equal(t, "test.Color.COLOR_RED", findField("color", paint).DefaultEnumAnchor)
+	require.Equal(t, findEnum("Color", tmpl.Files[0]).ValueAnchor("COLOR_RED"), findField("color", paint).DefaultEnumAnchor)
+	require.Empty(t, findField("unknown", paint).DefaultEnumAnchor)
+	require.Empty(t, findField("name", paint).DefaultEnumAnchor)
+}
+
+func TestEnumNumberGaps(t *testing.T) {
+	value := func(name string, number int32) *descriptor.EnumValueDescriptorProto {
+		return &descriptor.EnumValueDescriptorProto{Name: proto.String(name), Number: proto.Int32(number)}
+	}
+
+	tmpl := newTestTemplate(&descriptor.FileDescriptorProto{
+		Name:    proto.String("gaps.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		EnumType: []*descriptor.EnumDescriptorProto{
+			{
+				Name:    proto.String("Status"),
+				Options: &descriptor.EnumOptions{AllowAlias: proto.Bool(true)},
+				Value: []*descriptor.EnumValueDescriptorProto{
+					value("STATUS_UNSPECIFIED", 0),
+					value("STATUS_DONE", 5),
+					value("STATUS_FINISHED", 5),
+					value("STATUS_RUNNING", 2),
+					value("STATUS_FAILED", 6),
+				},
+			},
+			{
+				Name:  proto.String("Huge"),
+				Value: []*descriptor.EnumValueDescriptorProto{value("HUGE_MIN", -2147483648), value("HUGE_MAX", 2147483647)},
+			},
+		},
+	})
+
+	require.Equal(t, []int{1, 3, 4}, findEnum("Status", tmpl.Files[0]).NumberGaps())
+	require.Len(t, findEnum("Huge", tmpl.Files[0]).NumberGaps(), 1000)
+	require.Equal(t, -2147483647, findEnum("Huge", tmpl.Files[0]).NumberGaps()[0])
+	require.Empty(t, findEnum("BookingType", bookingFile).NumberGaps())
+	require.Empty(t, new(Enum).NumberGaps())
+}
+
+func TestParamDirectives(t *testing.T) {
+	directive := &Directive{Descrition: "Gets a thing.\n@param name The name of the thing.\n@param view\n@param name Ignored."}
+	require.Equal(t, map[string]string{"name": "The name of the thing.", "view": ""}, directive.Params())
+	require.Equal(t, "Gets a thing.\n\n\n", directive.Descrition)
+
+	directive = &Directive{Descrition: "Gets a thing."}
+	require.NotNil(t, directive.Params())
+	require.Empty(t, directive.Params())
+
+	tmpl := newTestTemplate(&descriptor.FileDescriptorProto{
+		Name:    proto.String("params.proto"),
+		Package: proto.String("test"),
+		Service: []*descriptor.ServiceDescriptorProto{{
+			Name: proto.String("ThingService"),
+			Method: []*descriptor.MethodDescriptorProto{
+				{Name: proto.String("GetThing"), InputType: proto.String(".test.Thing"), OutputType: proto.String(".test.Thing")},
+				{Name: proto.String("ListThings"), InputType: proto.String(".test.Thing"), OutputType: proto.String(".test.Thing")},
+			},
+		}},
+		SourceCodeInfo: &descriptor.SourceCodeInfo{
+			Location: []*descriptor.SourceCodeInfo_Location{
+				{Path: []int32{6, 0, 2, 0}, LeadingComments: proto.String(" Gets a thing. @param name The name of the thing.\n")},
+			},
+		},
+	})
+
+	service := findService("ThingService", tmpl.Files[0])
+	getThing := findServiceMethod("GetThing", service)
+	require.Equal(t, "Gets a thing. ", getThing.Description)
+	require.Equal(t, map[string]string{"name": "The name of the thing."}, getThing.ParamDocs)
+	require.Empty(t, findServiceMethod("ListThings", service).ParamDocs)
+}
+
+func TestInferredVerb(t *testing.T) {
+	method := func(name string) *descriptor.MethodDescriptorProto {
+		return &descriptor.MethodDescriptorProto{Name: proto.String(name), InputType: proto.String(".test.Thing"), OutputType: proto.String(".test.Thing")}
+	}
+
+	service := findService("ThingService", newTestTemplate(&descriptor.FileDescriptorProto{
+		Name:        proto.String("verbs.proto"),
+		Package:     proto.String("test"),
+		MessageType: []*descriptor.DescriptorProto{{Name: proto.String("Thing")}},
+		Service: []*descriptor.ServiceDescriptorProto{{
+			Name: proto.String("ThingService"),
+			Method: []*descriptor.MethodDescriptorProto{
+				method("GetThing"),
+				method("ListThings"),
+				method("CreateThing"),
+				method("UpdateThing"),
+				method("DeleteThing"),
+				method("BatchGetThings"),
+				method("Getaway"),
+				method("Get"),
+				method("PaintThing"),
+			},
+		}},
+	}).Files[0])
+
+	expected := map[string]string{
+		"GetThing":       "get",
+		"ListThings":     "list",
+		"CreateThing":    "create",
+		"UpdateThing":    "update",
+		"DeleteThing":    "delete",
+		"BatchGetThings": "batch",
+		"Getaway":        "",
+		"Get":            "get",
+		"PaintThing":     "",
+	}
+	for name, verb := range expected {
+		require.Equal(t, verb, findServiceMethod(name, service).InferredVerb, name)
+	}
+}
+
+func TestServiceAndTypesOnlyFiles(t *testing.T) {
+	require.True(t, bookingFile.IsServiceFile)
+	require.False(t, bookingFile.IsTypesOnly)
+
+	tmpl := newTestTemplate(
+		&descriptor.FileDescriptorProto{
+			Name:        proto.String("types.proto"),
+			Package:     proto.String("test"),
+			MessageType: []*descriptor.DescriptorProto{{Name: proto.String("Thing")}},
+		},
+		&descriptor.FileDescriptorProto{
+			Name:     proto.String("enums.proto"),
+			Package:  proto.String("test"),
+			EnumType: []*descriptor.EnumDescriptorProto{{Name: proto.String("Kind")}},
+		},
+		&descriptor.FileDescriptorProto{Name: proto.String("empty.proto"), Package: proto.String("test")},
+	)
+
+	for _, f := range tmpl.Files[:2] {
+		require.False(t, f.IsServiceFile, f.Name)
+		require.True(t, f.IsTypesOnly, f.Name)
+	}
+	require.False(t, tmpl.Files[2].IsServiceFile)
+	require.False(t, tmpl.Files[2].IsTypesOnly)
+}
+
+func TestLangDirectives(t *testing.T) {
+	directive := &Directive{Descrition: "A thing.\n@lang:es Una cosa.\n@lang:pt-BR Uma coisa.\n@lang:es Otra cosa."}
+	require.Equal(t, map[string]string{"es": "Una cosa.", "pt-BR": "Uma coisa."}, directive.Translations())
+	require.Equal(t, "A thing.", directive.Descrition)
+
+	directive = &Directive{Descrition: "A thing."}
+	require.Nil(t, directive.Translations())
+	require.Equal(t, "A thing.", directive.Descrition)
+
+	tmpl := newTestTemplate(&descriptor.FileDescriptorProto{
+		Name:    proto.String("lang.proto"),
+		Package: proto.String("test"),
+		MessageType: []*descriptor.DescriptorProto{{
+			Name:  proto.String("Thing"),
+			Field: []*descriptor.FieldDescriptorProto{newTestField("name", 1, descriptor.FieldDescriptorProto_TYPE_STRING, "")},
+		}},
+		SourceCodeInfo: &descriptor.SourceCodeInfo{
+			Location: []*descriptor.SourceCodeInfo_Location{
+				{Path: []int32{4, 0}, LeadingComments: proto.String(" A thing. @beta\n @lang:de Ein Ding.\n")},
+			},
+		},
+	})
+
+	thing := findMessage("Thing", tmpl.Files[0])
+	require.Equal(t, "A thing.", thing.Description)
+	require.Equal(t, map[string]string{"de": "Ein Ding."}, thing.Descriptions)
+	require.Equal(t, "beta", thing.Stability)
+	require.Nil(t, findField("name", thing).Descriptions)
+}
+
+func TestEmbedDirective(t *testing.T) {
+	directive := &Directive{Descrition: "A thing.\n@embed:begin\n<svg>\n  <text>@exclude</text>\n</svg>\n@embed:end\nMore.\n@embed:begin\ngraph TD;\n@embed:end"}
+	require.Equal(t, []string{"<svg>\n  <text>@exclude</text>\n</svg>", "graph TD;"}, directive.Embeds())
+	require.Equal(t, "A thing.\n\nMore.", directive.Descrition)
+	require.False(t, directive.Exclude())
+
+	directive = &Directive{Descrition: "A thing."}
+	require.NotNil(t, directive.Embeds())
+	require.Empty(t, directive.Embeds())
+
+	tmpl := newTestTemplate(&descriptor.FileDescriptorProto{
+		Name:    proto.String("embed.proto"),
+		Package: proto.String("test"),
+		MessageType: []*descriptor.DescriptorProto{{
+			Name:  proto.String("Thing"),
+			Field: []*descriptor.FieldDescriptorProto{newTestField("name", 1, descriptor.FieldDescriptorProto_TYPE_STRING, "")},
+		}},
+		SourceCodeInfo: &descriptor.SourceCodeInfo{
+			Location: []*descriptor.SourceCodeInfo_Location{
+				{Path: []int32{4, 0}, LeadingComments: proto.String(" A thing.\n @embed:begin\n flowchart LR\n   A --> B\n @embed:end\n")},
+			},
+		},
+	})
+
+	thing := findMessage("Thing", tmpl.Files[0])
+	require.Equal(t, "A thing.", thing.Description)
+	require.Equal(t, []string{"flowchart LR\n  A --> B"}, thing.EmbeddedBlocks)
+	require.Empty(t, findField("name", thing).EmbeddedBlocks)
+}
+
+func TestRootPackage(t *testing.T) {
+	types := &descriptor.FileDescriptorProto{
+		Name:        proto.String("types.proto"),
+		Package:     proto.String("mycompany.api.v1"),
+		Syntax:      proto.String("proto3"),
+		MessageType: []*descriptor.DescriptorProto{{Name: proto.String("Foo")}},
+	}
+	service := &descriptor.FileDescriptorProto{
+		Name:       proto.String("billing.proto"),
+		Package:    proto.String("mycompany.api.v1.billing"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"types.proto"},
+		MessageType: []*descriptor.DescriptorProto{{
+			Name: proto.String("Invoice"),
+			Field: []*descriptor.FieldDescriptorProto{
+				newTestField("foo", 1, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".mycompany.api.v1.Foo"),
+				newTestField("other", 2, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.Timestamp"),
+			},
+		}},
+		Service: []*descriptor.ServiceDescriptorProto{{
+			Name: proto.String("Billing"),
+			Method: []*descriptor.MethodDescriptorProto{{
+				Name:       proto.String("GetFoo"),
+				InputType:  proto.String(".mycompany.api.v1.billing.Invoice"),
+				OutputType: proto.String(".mycompany.api.v1.Foo"),
+			}},
+		}},
+	}
+
+	tmpl := newTestTemplateWithOptions(TemplateOptions{RootPackage: "mycompany.api.v1"}, types, service)
+	require.Equal(t, "mycompany.api.v1", tmpl.RootPackage)
+
+	invoice := findMessage("Invoice", tmpl.Files[1])
+	require.Equal(t, "Foo", findField("foo", invoice).LongType)
+	require.Equal(t, "mycompany.api.v1.Foo", findField("foo", invoice).FullType)
+	require.Equal(t, "google.protobuf.Timestamp", findField("other", invoice).LongType)
+
+	method := findServiceMethod("GetFoo", findService("Billing", tmpl.Files[1]))
+	require.Equal(t, "Invoice", method.RequestLongType)
+	require.Equal(t, "Foo", method.ResponseLongType)
+	require.Equal(t, "mycompany.api.v1.Foo", method.ResponseFullType)
+
+	require.Equal(t, "billing.Invoice", tmpl.RelativeName(".mycompany.api.v1.billing.Invoice"))
+	require.Equal(t, "mycompany.api.v1x.Bar", tmpl.RelativeName("mycompany.api.v1x.Bar"))
+	require.Equal(t, "mycompany.api.v1.Foo", new(Template).RelativeName(".mycompany.api.v1.Foo"))
+
+	withoutRoot := newTestTemplate(types, service)
+	require.Equal(t, "mycompany.api.v1.Foo", findField("foo", findMessage("Invoice", withoutRoot.Files[1])).LongType)
+}
+
+func TestWellKnownMethodTypes(t *testing.T) {
+	method := func(name, in, out string) *descriptor.MethodDescriptorProto {
+		return &descriptor.MethodDescriptorProto{Name: proto.String(name), InputType: proto.String(in), OutputType: proto.String(out)}
+	}
+
+	service := findService("ThingService", newTestTemplate(&descriptor.FileDescriptorProto{
+		Name:        proto.String("wellknown.proto"),
+		Package:     proto.String("test"),
+		MessageType: []*descriptor.DescriptorProto{{Name: proto.String("Thing")}},
+		Service: []*descriptor.ServiceDescriptorProto{{
+			Name: proto.String("ThingService"),
+			Method: []*descriptor.MethodDescriptorProto{
+				method("Ping", ".google.protobuf.Empty", ".google.protobuf.Empty"),
+				method("Wrap", ".google.protobuf.Any", ".google.protobuf.Struct"),
+				method("Get", ".test.Thing", ".google.protobuf.Timestamp"),
+			},
+		}},
+	}).Files[0])
+
+	ping := findServiceMethod("Ping", service)
+	require.True(t, ping.RequestIsWellKnown)
+	require.Equal(t, WellKnownEmpty, ping.RequestWellKnownKind)
+	require.True(t, ping.ResponseIsWellKnown)
+	require.Equal(t, WellKnownEmpty, ping.ResponseWellKnownKind)
+
+	wrap := findServiceMethod("Wrap", service)
+	require.Equal(t, WellKnownAny, wrap.RequestWellKnownKind)
+	require.Equal(t, WellKnownStruct, wrap.ResponseWellKnownKind)
+
+	get := findServiceMethod("Get", service)
+	require.False(t, get.RequestIsWellKnown)
+	require.Empty(t, get.RequestWellKnownKind)
+	require.False(t, get.ResponseIsWellKnown)
+	require.Empty(t, get.ResponseWellKnownKind)
+}
+
+// newTestTemplate builds a Template from hand-written descriptors. All supplied files are generated.
+func newTestTemplate(fds ...*descriptor.FileDescriptorProto) *Template {
+	req := new(plugin_go.CodeGeneratorRequest)
+	for _, fd := range fds {
+		req.FileToGenerate = append(req.FileToGenerate, fd.GetName())
+		req.ProtoFile = append(req.ProtoFile, fd)
+	}
+
+	return NewTemplate(protokit.ParseCodeGenRequest(req))
+}
+
+func newTestTemplateWithOptions(opts TemplateOptions, fds ...*descriptor.FileDescriptorProto) *Template {
+	req := new(plugin_go.CodeGeneratorRequest)
+	for _, fd := range fds {
+		req.FileToGenerate = append(req.FileToGenerate, fd.GetName())
+		req.ProtoFile = append(req.ProtoFile, fd)
+	}
+
+	return NewTemplateWithOptions(protokit.ParseCodeGenRequest(req), opts)
+}
+
+func newFixtureTemplateWithOptions(opts TemplateOptions) *Template {
+	set, _ := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	req := utils.CreateGenRequest(set, "Booking.proto", "Vehicle.proto")
+
+	return NewTemplateWithOptions(protokit.ParseCodeGenRequest(req), opts)
+}
+
+func newTestField(name string, number int32, t descriptor.FieldDescriptorProto_Type, typeName string) *descriptor.FieldDescriptorProto {
+	field := &descriptor.FieldDescriptorProto{
+		Name:   proto.String(name),
+		Number: proto.Int32(number),
+		Label:  descriptor.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		Type:   t.Enum(),
+	}
+	if typeName != "" {
+		field.TypeName = proto.String(typeName)
+	}
+
+	return field
+}
+
+func newTestMapEntry(name string, value *descriptor.FieldDescriptorProto) *descriptor.DescriptorProto {
+	value.Name = proto.String("value")
+	value.Number = proto.Int32(2)
+
+	return &descriptor.DescriptorProto{
+		Name: proto.String(name),
+		Field: []*descriptor.FieldDescriptorProto{
+			newTestField("key", 1, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+			value,
+		},
+		Options: &descriptor.MessageOptions{MapEntry: proto.Bool(true)},
+	}
+}
+
 func findService(name string, f *File) *Service {
 	for _, s := range f.Services {
 		if s.Name == name {
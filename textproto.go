@@ -0,0 +1,91 @@
+package gendoc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// textProtoDepth limits how many levels of nested messages are expanded by Message.TextProtoSkeleton.
+const textProtoDepth = 3
+
+// TextProtoSkeleton returns a protobuf text format skeleton of the message, with a placeholder value for each field and
+// the summary of its description as a comment. It's meant as a starting point for config files, e.g.
+//
+//	# com.example.Vehicle
+//	# The vehicle's id.
+//	id: 0
+//	tags: [""]
+//	engine {
+//	  power: 0
+//	}
+//
+// Repeated (and map) fields show a list with a single placeholder. Message fields are expanded up to 3 levels deep, and
+// not at all when they're recursive or defined outside of the Template, in which case they're left empty. Fields
+// marked with `@no_schema` are left out.
+func (m Message) TextProtoSkeleton() string {
+	b := new(strings.Builder)
+	fmt.Fprintf(b, "# %s\n", m.FullName)
+	writeTextProtoFields(b, &m, "", 1, map[string]bool{m.FullName: true})
+
+	return b.String()
+}
+
+// writeTextProtoFields writes the fields of msg, indented by indent. Messages in seen are on the current path and won't
+// be expanded again.
+func writeTextProtoFields(b *strings.Builder, msg *Message, indent string, depth int, seen map[string]bool) {
+	for _, field := range msg.Fields {
+		if field.NoSchema {
+			continue
+		}
+		if s := summary(field.Description); s != "" {
+			fmt.Fprintf(b, "%s# %s\n", indent, s)
+		}
+
+		repeated := field.Label == "repeated" || field.IsMap
+		if field.TypeKind != typeKindMessage && !field.IsMap {
+			if repeated {
+				fmt.Fprintf(b, "%s%s: [%s]\n", indent, field.Name, textProtoValue(field))
+			} else {
+				fmt.Fprintf(b, "%s%s: %s\n", indent, field.Name, textProtoValue(field))
+			}
+			continue
+		}
+
+		open, close := " {", "}"
+		if repeated {
+			open, close = ": [{", "}]"
+		}
+		child := field.typeMessage
+		if child == nil || depth >= textProtoDepth || seen[child.FullName] {
+			fmt.Fprintf(b, "%s%s%s%s\n", indent, field.Name, open, close)
+			continue
+		}
+
+		seen[child.FullName] = true
+		fmt.Fprintf(b, "%s%s%s\n", indent, field.Name, open)
+		writeTextProtoFields(b, child, indent+"  ", depth+1, seen)
+		fmt.Fprintf(b, "%s%s\n", indent, close)
+		delete(seen, child.FullName)
+	}
+}
+
+// textProtoValue returns the placeholder value of a scalar or enum field.
+func textProtoValue(field *MessageField) string {
+	if field.TypeKind == typeKindEnum {
+		if field.typeEnum != nil && len(field.typeEnum.Values) > 0 {
+			return field.typeEnum.Values[0].Name
+		}
+		return "0"
+	}
+
+	switch field.Type {
+	case "bool":
+		return "false"
+	case "string", "bytes":
+		return `""`
+	case "double", "float":
+		return "0.0"
+	}
+
+	return "0"
+}
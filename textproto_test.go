@@ -0,0 +1,78 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTextProtoSkeleton(t *testing.T) {
+	repeated := descriptor.FieldDescriptorProto_LABEL_REPEATED
+	tags := newTestField("tags", 3, descriptor.FieldDescriptorProto_TYPE_STRING, "")
+	tags.Label = &repeated
+	parts := newTestField("parts", 5, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".test.Part")
+	parts.Label = &repeated
+	labels := newTestField("labels", 7, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".test.Thing.LabelsEntry")
+	labels.Label = &repeated
+
+	tmpl := newTestTemplate(&descriptor.FileDescriptorProto{
+		Name:    proto.String("textproto.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptor.DescriptorProto{
+			{
+				Name: proto.String("Thing"),
+				Field: []*descriptor.FieldDescriptorProto{
+					newTestField("name", 1, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+					newTestField("weight", 2, descriptor.FieldDescriptorProto_TYPE_DOUBLE, ""),
+					tags,
+					newTestField("color", 4, descriptor.FieldDescriptorProto_TYPE_ENUM, ".test.Color"),
+					parts,
+					newTestField("created_at", 6, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.Timestamp"),
+					labels,
+					newTestField("etag", 8, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+				},
+				NestedType: []*descriptor.DescriptorProto{
+					newTestMapEntry("LabelsEntry", newTestField("", 0, descriptor.FieldDescriptorProto_TYPE_STRING, "")),
+				},
+			},
+			{
+				Name: proto.String("Part"),
+				Field: []*descriptor.FieldDescriptorProto{
+					newTestField("enabled", 1, descriptor.FieldDescriptorProto_TYPE_BOOL, ""),
+					newTestField("parent", 2, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".test.Part"),
+				},
+			},
+		},
+		EnumType: []*descriptor.EnumDescriptorProto{{
+			Name:  proto.String("Color"),
+			Value: []*descriptor.EnumValueDescriptorProto{{Name: proto.String("COLOR_UNSPECIFIED"), Number: proto.Int32(0)}},
+		}},
+		SourceCodeInfo: &descriptor.SourceCodeInfo{
+			Location: []*descriptor.SourceCodeInfo_Location{
+				{Path: []int32{4, 0, 2, 0}, LeadingComments: proto.String(" The name of the thing,\n e.g. \"box\".\n\n Must be unique.\n")},
+				{Path: []int32{4, 0, 2, 7}, LeadingComments: proto.String(" @no_schema\n")},
+			},
+		},
+	})
+
+	expected := `# test.Thing
+# The name of the thing, e.g. "box".
+name: ""
+weight: 0.0
+tags: [""]
+color: COLOR_UNSPECIFIED
+parts: [{
+  enabled: false
+  parent {}
+}]
+created_at {}
+labels: [{
+  key: ""
+  value: ""
+}]
+`
+	require.Equal(t, expected, findMessage("Thing", tmpl.Files[0]).TextProtoSkeleton())
+}
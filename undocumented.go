@@ -0,0 +1,73 @@
+package gendoc
+
+import (
+	"sort"
+)
+
+// Undocumented returns the full names of the messages, fields, enums, and service methods that don't have a
+// description (after directives have been stripped, see HasDescription). Excluded elements, including everything
+// within excluded files, messages, and services, aren't reported. Neither are the synthetic entry messages of map
+// fields.
+//
+// The names are sorted, which makes the result suitable for doc coverage checks in CI.
+func (t *Template) Undocumented() []string {
+	var names []string
+	undocumented := func(fullName string, hasDescription bool) {
+		if !hasDescription {
+			names = append(names, fullName)
+		}
+	}
+
+	for _, f := range t.Files {
+		if f.Exclude {
+			continue
+		}
+
+		mapEntries := mapEntryNames(f)
+		for _, m := range f.Messages {
+			if m.Exclude || mapEntries[m.FullName] {
+				continue
+			}
+
+			undocumented(m.FullName, m.HasDescription)
+			for _, field := range m.Fields {
+				undocumented(m.FullName+"."+field.Name, field.HasDescription)
+			}
+		}
+
+		for _, e := range f.Enums {
+			if !e.Exclude {
+				undocumented(e.FullName, e.HasDescription)
+			}
+		}
+
+		for _, s := range f.Services {
+			if s.Exclude {
+				continue
+			}
+
+			for _, m := range s.Methods {
+				if !m.Exclude {
+					undocumented(s.FullName+"."+m.Name, m.HasDescription)
+				}
+			}
+		}
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// mapEntryNames returns the full names of the synthetic entry messages of the map fields in f.
+func mapEntryNames(f *File) map[string]bool {
+	mapEntries := make(map[string]bool)
+	for _, m := range f.Messages {
+		for _, field := range m.Fields {
+			if field.IsMap {
+				mapEntries[field.FullType] = true
+			}
+		}
+	}
+
+	return mapEntries
+}
@@ -0,0 +1,60 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUndocumented(t *testing.T) {
+	comment := func(text string, path ...int32) *descriptor.SourceCodeInfo_Location {
+		return &descriptor.SourceCodeInfo_Location{Path: path, LeadingComments: proto.String(text)}
+	}
+
+	tmpl := newTestTemplate(&descriptor.FileDescriptorProto{
+		Name:    proto.String("coverage.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptor.DescriptorProto{
+			{
+				Name: proto.String("Thing"),
+				Field: []*descriptor.FieldDescriptorProto{
+					newTestField("id", 1, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+					newTestField("name", 2, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+				},
+			},
+			{
+				Name:  proto.String("Hidden"),
+				Field: []*descriptor.FieldDescriptorProto{newTestField("id", 1, descriptor.FieldDescriptorProto_TYPE_STRING, "")},
+			},
+		},
+		EnumType: []*descriptor.EnumDescriptorProto{{
+			Name:  proto.String("Kind"),
+			Value: []*descriptor.EnumValueDescriptorProto{{Name: proto.String("KIND_UNSPECIFIED"), Number: proto.Int32(0)}},
+		}},
+		Service: []*descriptor.ServiceDescriptorProto{{
+			Name: proto.String("ThingService"),
+			Method: []*descriptor.MethodDescriptorProto{
+				{Name: proto.String("GetThing"), InputType: proto.String(".test.Thing"), OutputType: proto.String(".test.Thing")},
+				{Name: proto.String("Debug"), InputType: proto.String(".test.Thing"), OutputType: proto.String(".test.Thing")},
+			},
+		}},
+		SourceCodeInfo: &descriptor.SourceCodeInfo{
+			Location: []*descriptor.SourceCodeInfo_Location{
+				comment(" A thing.\n", 4, 0),
+				comment(" The ID.\n", 4, 0, 2, 0),
+				comment(" @exclude\n", 4, 1),
+				comment(" @beta\n", 6, 0, 2, 0),
+				comment(" @exclude\n", 6, 0, 2, 1),
+			},
+		},
+	})
+
+	require.Equal(t, []string{
+		"test.Kind",
+		"test.Thing.name",
+		"test.ThingService.GetThing",
+	}, tmpl.Undocumented())
+}
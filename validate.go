@@ -0,0 +1,90 @@
+package gendoc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationWarning describes a problem with one of the elements of a Template. Warnings don't prevent documentation
+// from being generated, but usually point at definitions that protoc (or the docs) won't handle as expected.
+type ValidationWarning struct {
+	FullName string `json:"fullName"`
+	Message  string `json:"message"`
+}
+
+func (w *ValidationWarning) String() string {
+	return fmt.Sprintf("%s: %s", w.FullName, w.Message)
+}
+
+// Validate checks the parsed files for problems and returns a warning for each one found, or nil if everything is
+// fine. The following checks are made:
+//
+// * enums in proto3 files must have a zero value
+// * fields of a message must have distinct JSON names (e.g. `foo_bar` and `fooBar` both map to "fooBar")
+// * fields of a message must have distinct numbers
+func (t *Template) Validate() []*ValidationWarning {
+	var warnings []*ValidationWarning
+
+	for _, f := range t.Files {
+		if f.Syntax == "proto3" {
+			for _, e := range f.Enums {
+				if e.ZeroValue == nil {
+					warnings = append(warnings, &ValidationWarning{
+						FullName: e.FullName,
+						Message:  "proto3 enums must have a value numbered 0 (the default value)",
+					})
+				}
+			}
+		}
+
+		for _, m := range f.Messages {
+			warnings = append(warnings, duplicateJSONNames(m)...)
+			warnings = append(warnings, duplicateNumbers(m)...)
+		}
+	}
+
+	return warnings
+}
+
+func duplicateJSONNames(m *Message) []*ValidationWarning {
+	var warnings []*ValidationWarning
+
+	seen := make(map[string]string)
+	for _, field := range m.Fields {
+		if other, ok := seen[field.JSONName]; ok {
+			warnings = append(warnings, &ValidationWarning{
+				FullName: m.FullName,
+				Message:  fmt.Sprintf("fields %s and %s have the same JSON name %q", other, field.Name, field.JSONName),
+			})
+			continue
+		}
+		seen[field.JSONName] = field.Name
+	}
+
+	return warnings
+}
+
+// duplicateNumbers reports the field numbers used by more than one field of m, one warning per number. protoc doesn't
+// allow this, but hand-edited or merged descriptors may contain them.
+func duplicateNumbers(m *Message) []*ValidationWarning {
+	var numbers []int
+	fields := make(map[int][]string)
+	for _, field := range m.Fields {
+		if _, ok := fields[field.Number]; !ok {
+			numbers = append(numbers, field.Number)
+		}
+		fields[field.Number] = append(fields[field.Number], field.Name)
+	}
+
+	var warnings []*ValidationWarning
+	for _, number := range numbers {
+		if names := fields[number]; len(names) > 1 {
+			warnings = append(warnings, &ValidationWarning{
+				FullName: m.FullName,
+				Message:  fmt.Sprintf("fields %s have the same number %d", strings.Join(names, ", "), number),
+			})
+		}
+	}
+
+	return warnings
+}
@@ -0,0 +1,139 @@
+package gendoc_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+	. "github.com/pseudomuto/protoc-gen-doc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnumZeroValue(t *testing.T) {
+	enum := findEnum("Manufacturer.Category", vehicleFile)
+	require.NotNil(t, enum.ZeroValue)
+	require.Equal(t, "0", enum.ZeroValue.Number)
+	require.True(t, enum.Values[0] == enum.ZeroValue)
+}
+
+func TestValidateEnumZeroValue(t *testing.T) {
+	require.Empty(t, template.Validate())
+
+	enum := func(name string, numbers ...int32) *descriptor.EnumDescriptorProto {
+		e := &descriptor.EnumDescriptorProto{Name: proto.String(name)}
+		for _, n := range numbers {
+			e.Value = append(e.Value, &descriptor.EnumValueDescriptorProto{
+				Name:   proto.String(fmt.Sprintf("%s_%d", name, n)),
+				Number: proto.Int32(n),
+			})
+		}
+		return e
+	}
+
+	tmpl := newTestTemplate(
+		&descriptor.FileDescriptorProto{
+			Name:     proto.String("proto2.proto"),
+			Package:  proto.String("test"),
+			EnumType: []*descriptor.EnumDescriptorProto{enum("Legacy", 1, 2)},
+		},
+		&descriptor.FileDescriptorProto{
+			Name:     proto.String("proto3.proto"),
+			Package:  proto.String("test"),
+			Syntax:   proto.String("proto3"),
+			EnumType: []*descriptor.EnumDescriptorProto{enum("Good", 0, 1), enum("Bad", 1, 2)},
+		},
+	)
+
+	require.Nil(t, findEnum("Legacy", tmpl.Files[0]).ZeroValue)
+	require.Equal(t, []*ValidationWarning{
+		{FullName: "test.Bad", Message: "proto3 enums must have a value numbered 0 (the default value)"},
+	}, tmpl.Validate())
+}
+
+func TestFieldJSONName(t *testing.T) {
+	require.Equal(t, "kilometers", findField("kilometers", findMessage("Vehicle", vehicleFile)).JSONName)
+
+	tmpl := newTestTemplate(&descriptor.FileDescriptorProto{
+		Name:    proto.String("json.proto"),
+		Package: proto.String("test"),
+		MessageType: []*descriptor.DescriptorProto{{
+			Name:  proto.String("Thing"),
+			Field: []*descriptor.FieldDescriptorProto{newTestField("created_at_ms", 1, descriptor.FieldDescriptorProto_TYPE_INT64, "")},
+		}},
+	})
+	require.Equal(t, "createdAtMs", findField("created_at_ms", findMessage("Thing", tmpl.Files[0])).JSONName)
+}
+
+func TestFieldHasCustomJSONName(t *testing.T) {
+	explicitDefault := newTestField("display_name", 1, descriptor.FieldDescriptorProto_TYPE_STRING, "")
+	explicitDefault.JsonName = proto.String("displayName")
+	custom := newTestField("created_at_ms", 2, descriptor.FieldDescriptorProto_TYPE_INT64, "")
+	custom.JsonName = proto.String("created")
+
+	thing := findMessage("Thing", newTestTemplate(&descriptor.FileDescriptorProto{
+		Name:    proto.String("json.proto"),
+		Package: proto.String("test"),
+		MessageType: []*descriptor.DescriptorProto{{
+			Name: proto.String("Thing"),
+			Field: []*descriptor.FieldDescriptorProto{
+				explicitDefault,
+				custom,
+				newTestField("note", 3, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+			},
+		}},
+	}).Files[0])
+
+	require.False(t, findField("display_name", thing).HasCustomJSONName)
+	require.True(t, findField("created_at_ms", thing).HasCustomJSONName)
+	require.Equal(t, "created", findField("created_at_ms", thing).JSONName)
+	require.False(t, findField("note", thing).HasCustomJSONName)
+}
+
+func TestValidateDuplicateJSONNames(t *testing.T) {
+	custom := newTestField("other", 3, descriptor.FieldDescriptorProto_TYPE_STRING, "")
+	custom.JsonName = proto.String("fooBar")
+
+	tmpl := newTestTemplate(&descriptor.FileDescriptorProto{
+		Name:    proto.String("json.proto"),
+		Package: proto.String("test"),
+		MessageType: []*descriptor.DescriptorProto{{
+			Name: proto.String("Thing"),
+			Field: []*descriptor.FieldDescriptorProto{
+				newTestField("foo_bar", 1, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+				newTestField("fooBar", 2, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+				custom,
+			},
+		}},
+	})
+
+	require.Equal(t, []*ValidationWarning{
+		{FullName: "test.Thing", Message: `fields foo_bar and fooBar have the same JSON name "fooBar"`},
+		{FullName: "test.Thing", Message: `fields foo_bar and other have the same JSON name "fooBar"`},
+	}, tmpl.Validate())
+}
+
+func TestValidateDuplicateNumbers(t *testing.T) {
+	tmpl := newTestTemplate(&descriptor.FileDescriptorProto{
+		Name:    proto.String("numbers.proto"),
+		Package: proto.String("test"),
+		MessageType: []*descriptor.DescriptorProto{{
+			Name: proto.String("Thing"),
+			Field: []*descriptor.FieldDescriptorProto{
+				newTestField("name", 1, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+				newTestField("size", 2, descriptor.FieldDescriptorProto_TYPE_INT32, ""),
+				newTestField("title", 1, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+				newTestField("weight", 2, descriptor.FieldDescriptorProto_TYPE_INT32, ""),
+				newTestField("label", 1, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+				newTestField("color", 3, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+			},
+		}},
+	})
+
+	require.Equal(t, []*ValidationWarning{
+		{FullName: "test.Thing", Message: "fields name, title, label have the same number 1"},
+		{FullName: "test.Thing", Message: "fields size, weight have the same number 2"},
+	}, tmpl.Validate())
+
+	require.Empty(t, template.Validate())
+}
@@ -0,0 +1,56 @@
+package gendoc
+
+// WalkMessages calls fn for every message in the template, including nested messages (and map entries), in the order
+// of Files and File.Messages. Excluded elements aren't skipped, fn can check Exclude when needed.
+func (t *Template) WalkMessages(fn func(file *File, msg *Message)) {
+	for _, f := range t.Files {
+		for _, m := range f.Messages {
+			fn(f, m)
+		}
+	}
+}
+
+// WalkFields calls fn for every field of every message in the template (see WalkMessages), along with the file and
+// message it's defined in.
+func (t *Template) WalkFields(fn func(file *File, msg *Message, field *MessageField)) {
+	t.WalkMessages(func(f *File, m *Message) {
+		for _, field := range m.Fields {
+			fn(f, m, field)
+		}
+	})
+}
+
+// WalkEnums calls fn for every enum in the template, including enums nested in messages.
+func (t *Template) WalkEnums(fn func(file *File, enum *Enum)) {
+	for _, f := range t.Files {
+		for _, e := range f.Enums {
+			fn(f, e)
+		}
+	}
+}
+
+// WalkMethods calls fn for every service method in the template, along with the file and service it's defined in.
+func (t *Template) WalkMethods(fn func(file *File, service *Service, method *ServiceMethod)) {
+	for _, f := range t.Files {
+		for _, s := range f.Services {
+			for _, m := range s.Methods {
+				fn(f, s, m)
+			}
+		}
+	}
+}
+
+// StreamingMethods returns the service methods in the template that stream requests, responses, or both, in the order
+// of WalkMethods. Methods that are excluded (or whose service or file is) are left out.
+func (t *Template) StreamingMethods() []*ServiceMethod {
+	methods := make([]*ServiceMethod, 0)
+	t.WalkMethods(func(f *File, s *Service, m *ServiceMethod) {
+		if f.Exclude || s.Exclude || m.Exclude {
+			return
+		}
+		if m.RequestStreaming || m.ResponseStreaming {
+			methods = append(methods, m)
+		}
+	})
+	return methods
+}
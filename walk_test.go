@@ -0,0 +1,92 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+	. "github.com/pseudomuto/protoc-gen-doc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalkMessages(t *testing.T) {
+	count := 0
+	template.WalkMessages(func(file *File, msg *Message) {
+		require.Contains(t, file.Messages, msg)
+		count++
+	})
+
+	require.Equal(t, len(bookingFile.Messages)+len(vehicleFile.Messages), count)
+}
+
+func TestWalkFields(t *testing.T) {
+	var names []string
+	template.WalkFields(func(file *File, msg *Message, field *MessageField) {
+		require.Contains(t, msg.Fields, field)
+		if file == vehicleFile && msg.LongName == "Vehicle" {
+			names = append(names, field.Name)
+		}
+	})
+
+	var expected []string
+	for _, field := range findMessage("Vehicle", vehicleFile).Fields {
+		expected = append(expected, field.Name)
+	}
+	require.Equal(t, expected, names)
+}
+
+func TestWalkEnums(t *testing.T) {
+	var names []string
+	template.WalkEnums(func(file *File, enum *Enum) {
+		if file == bookingFile {
+			names = append(names, enum.LongName)
+		}
+	})
+
+	require.Contains(t, names, "BookingStatus.StatusCode")
+	require.Len(t, names, len(bookingFile.Enums))
+}
+
+func TestWalkMethods(t *testing.T) {
+	var names []string
+	template.WalkMethods(func(file *File, service *Service, method *ServiceMethod) {
+		require.Contains(t, file.Services, service)
+		names = append(names, service.Name+"."+method.Name)
+	})
+
+	require.Contains(t, names, "VehicleService.GetModels")
+	require.Contains(t, names, "BookingService.BookVehicle")
+}
+
+func TestStreamingMethods(t *testing.T) {
+	service := findService("VehicleService", vehicleFile)
+	require.Equal(t, []*ServiceMethod{
+		findServiceMethod("GetModels", service),
+		findServiceMethod("AddModels", service),
+	}, template.StreamingMethods())
+
+	method := func(name string, clientStreaming bool) *descriptor.MethodDescriptorProto {
+		return &descriptor.MethodDescriptorProto{
+			Name:            proto.String(name),
+			InputType:       proto.String(".test.Thing"),
+			OutputType:      proto.String(".test.Thing"),
+			ClientStreaming: proto.Bool(clientStreaming),
+		}
+	}
+	tmpl := newTestTemplate(&descriptor.FileDescriptorProto{
+		Name:    proto.String("streams.proto"),
+		Package: proto.String("test"),
+		Service: []*descriptor.ServiceDescriptorProto{{
+			Name:   proto.String("ThingService"),
+			Method: []*descriptor.MethodDescriptorProto{method("Upload", true), method("Sync", true), method("Get", false)},
+		}},
+		SourceCodeInfo: &descriptor.SourceCodeInfo{
+			Location: []*descriptor.SourceCodeInfo_Location{{Path: []int32{6, 0, 2, 1}, LeadingComments: proto.String(" @exclude\n")}},
+		},
+	})
+	require.Equal(t, []*ServiceMethod{
+		findServiceMethod("Upload", findService("ThingService", tmpl.Files[0])),
+	}, tmpl.StreamingMethods())
+
+	require.Empty(t, new(Template).StreamingMethods())
+}
@@ -0,0 +1,47 @@
+package gendoc
+
+import (
+	"strings"
+)
+
+// normalizeWhitespace collapses runs of spaces and tabs within each line of a description into a single space and trims
+// trailing whitespace. Leading indentation (e.g. of nested list items), fenced code blocks, and the padding of markdown
+// table rows are left untouched.
+func normalizeWhitespace(desc string) string {
+	lines := strings.Split(desc, "\n")
+	inFence := false
+
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			lines[i] = strings.TrimRight(line, " \t")
+			continue
+		}
+
+		if inFence {
+			continue
+		}
+
+		if isTableRow(line) {
+			lines[i] = strings.TrimRight(line, " \t")
+			continue
+		}
+
+		content := strings.TrimLeft(line, " \t")
+		indent := line[:len(line)-len(content)]
+		lines[i] = indent + strings.Join(strings.Fields(content), " ")
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// isTableRow reports whether line is a row (or the delimiter row) of a markdown pipe table.
+func isTableRow(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), "|")
+}
+
+func normalizeDescriptions(t *Template) {
+	eachDescription(t, func(desc *string, _ *bool) {
+		*desc = normalizeWhitespace(*desc)
+	})
+}
@@ -0,0 +1,58 @@
+package gendoc_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+	. "github.com/pseudomuto/protoc-gen-doc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeWhitespace(t *testing.T) {
+	comment := " A   thing\twith  gaps.   \n\n * first   item\n   * nested   item\n\n ```\n a  :=   1   \n ```\n"
+	fd := &descriptor.FileDescriptorProto{
+		Name:        proto.String("whitespace.proto"),
+		Package:     proto.String("test"),
+		MessageType: []*descriptor.DescriptorProto{{Name: proto.String("Thing")}},
+		SourceCodeInfo: &descriptor.SourceCodeInfo{
+			Location: []*descriptor.SourceCodeInfo_Location{
+				{Path: []int32{4, 0}, LeadingComments: proto.String(comment)},
+			},
+		},
+	}
+
+	tmpl := newTestTemplateWithOptions(TemplateOptions{NormalizeWhitespace: true}, fd)
+
+	require.Equal(t,
+		"A thing with gaps.\n\n* first item\n  * nested item\n\n```\na  :=   1   \n```",
+		findMessage("Thing", tmpl.Files[0]).Description,
+	)
+
+	require.Equal(t,
+		"A   thing\twith  gaps.   \n\n* first   item\n  * nested   item\n\n```\na  :=   1   \n```",
+		findMessage("Thing", newTestTemplate(fd).Files[0]).Description,
+	)
+}
+
+func TestMarkdownTablesSurviveCommentProcessing(t *testing.T) {
+	table := "| Region | Price  |\n|--------|-------:|\n| EU     | 10     |\n| US     | 12     |"
+	comment := " Prices by region.\n\n " + strings.ReplaceAll(table, "\n", "\n ") + "\n\n @beta\n"
+	fd := &descriptor.FileDescriptorProto{
+		Name:        proto.String("tables.proto"),
+		Package:     proto.String("test"),
+		MessageType: []*descriptor.DescriptorProto{{Name: proto.String("Price")}},
+		SourceCodeInfo: &descriptor.SourceCodeInfo{
+			Location: []*descriptor.SourceCodeInfo_Location{
+				{Path: []int32{4, 0}, LeadingComments: proto.String(comment)},
+			},
+		},
+	}
+	expected := "Prices by region.\n\n" + table + "\n\n"
+
+	require.Equal(t, expected, findMessage("Price", newTestTemplate(fd).Files[0]).Description)
+
+	tmpl := newTestTemplateWithOptions(TemplateOptions{NormalizeWhitespace: true}, fd)
+	require.Equal(t, expected, findMessage("Price", tmpl.Files[0]).Description)
+}